@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"github.com/go-logr/logr"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
 	"github.com/icinga/icinga-go-library/backoff"
@@ -16,36 +17,50 @@ import (
 	"github.com/icinga/icinga-kubernetes/internal"
 	cachev1 "github.com/icinga/icinga-kubernetes/internal/cache/v1"
 	"github.com/icinga/icinga-kubernetes/pkg/cluster"
-	"github.com/icinga/icinga-kubernetes/pkg/com"
 	"github.com/icinga/icinga-kubernetes/pkg/daemon"
 	kdatabase "github.com/icinga/icinga-kubernetes/pkg/database"
 	"github.com/icinga/icinga-kubernetes/pkg/metrics"
 	"github.com/icinga/icinga-kubernetes/pkg/notifications"
+	"github.com/icinga/icinga-kubernetes/pkg/rules"
 	schemav1 "github.com/icinga/icinga-kubernetes/pkg/schema/v1"
 	syncv1 "github.com/icinga/icinga-kubernetes/pkg/sync/v1"
+	"github.com/icinga/icinga-kubernetes/pkg/telemetry"
 	k8sMysql "github.com/icinga/icinga-kubernetes/schema/mysql"
+	k8sPgsql "github.com/icinga/icinga-kubernetes/schema/pgsql"
 	"github.com/okzk/sdnotify"
 	"github.com/pkg/errors"
-	promapi "github.com/prometheus/client_golang/api"
-	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/spf13/pflag"
 	"golang.org/x/sync/errgroup"
+	"io/fs"
+	kappsv1 "k8s.io/api/apps/v1"
+	kbatchv1 "k8s.io/api/batch/v1"
+	kcorev1 "k8s.io/api/core/v1"
+	knetworkingv1 "k8s.io/api/networking/v1"
+	kpolicyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/informers"
 	v2 "k8s.io/client-go/informers/core/v1"
+	kpolicyinformersv1 "k8s.io/client-go/informers/policy/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	kcache "k8s.io/client-go/tools/cache"
 	kclientcmd "k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
-	"net/http"
+	kmetrics "k8s.io/metrics/pkg/client/clientset/versioned"
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-const expectedSchemaVersion = "0.2.0"
+const expectedSchemaVersion = "0.3.0"
+
+// staleInstanceTimeout is the duration after which the leader removes instances that did not send a heartbeat.
+const staleInstanceTimeout = 5 * time.Minute
 
 func main() {
 	runtime.ReallyCrash = true
@@ -79,27 +94,10 @@ func main() {
 
 	klog.Infof("Starting Icinga for Kubernetes (%s)", internal.Version.Version)
 
-	kconfig, err := kclientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &overrides).ClientConfig()
-	if err != nil {
-		if kclientcmd.IsEmptyConfig(err) {
-			klog.Fatal(
-				"no configuration provided: set KUBECONFIG environment variable or --kubeconfig CLI flag to" +
-					" a kubeconfig file with cluster access configured")
-		}
-
-		klog.Fatal(errors.Wrap(err, "cannot configure Kubernetes client"))
-	}
-
-	clientset, err := kubernetes.NewForConfig(kconfig)
-	if err != nil {
-		klog.Fatal(err)
-	}
-
-	factory := informers.NewSharedInformerFactory(clientset, 0)
 	log := klog.NewKlogr()
 
 	var cfg daemon.Config
-	err = config.FromYAMLFile(configLocation, &cfg)
+	err := config.FromYAMLFile(configLocation, &cfg)
 	if err != nil {
 		klog.Fatal(errors.Wrap(err, "cannot create configuration"))
 	}
@@ -119,6 +117,15 @@ func main() {
 		return
 	}
 
+	var schema string
+	var upgrades fs.FS
+	switch kdb.DriverName() {
+	case kdatabase.MySQL:
+		schema, upgrades = k8sMysql.Schema, k8sMysql.Upgrades
+	case kdatabase.PostgreSQL:
+		schema, upgrades = k8sPgsql.Schema, k8sPgsql.Upgrades
+	}
+
 	hasSchema, err := dbHasSchema(kdb, cfg.Database.Database)
 	if err != nil {
 		klog.Fatal(err)
@@ -132,7 +139,7 @@ func main() {
 		err = retry.WithBackoff(
 			ctx,
 			func(ctx context.Context) (err error) {
-				query := "SELECT version FROM kubernetes_schema ORDER BY id DESC LIMIT 1"
+				query := "SELECT version FROM kubernetes_schema WHERE success = 'y' ORDER BY id DESC LIMIT 1"
 				err = kdb.QueryRowxContext(ctx, query).Scan(&version)
 				if err != nil {
 					err = kdatabase.CantPerformQuery(err, query)
@@ -147,50 +154,14 @@ func main() {
 		}
 
 		if version != expectedSchemaVersion {
-			err = retry.WithBackoff(
-				ctx,
-				func(ctx context.Context) (err error) {
-					rows, err := kdb.Query(
-						kdb.Rebind("SELECT table_name FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA=?"),
-						cfg.Database.Database,
-					)
-					if err != nil {
-						klog.Fatal(err)
-					}
-					defer func() {
-						_ = rows.Close()
-					}()
-
-					dbLog.Info("Dropping schema")
-
-					for rows.Next() {
-						var tableName string
-						if err := rows.Scan(&tableName); err != nil {
-							klog.Fatal(err)
-						}
-
-						_, err := kdb.Exec(fmt.Sprintf(`DROP TABLE %s`, tableName))
-						if err != nil {
-							klog.Fatal(err)
-						}
-					}
-					return
-				},
-				retry.Retryable,
-				backoff.NewExponentialWithJitter(128*time.Millisecond, 1*time.Minute),
-				retry.Settings{})
-			if err != nil {
+			if err := kdb.UpgradeSchema(ctx, upgrades, version, expectedSchemaVersion); err != nil {
 				klog.Fatal(err)
 			}
-
-			hasSchema = false
 		}
-	}
-
-	if !hasSchema {
+	} else {
 		dbLog.Info("Importing schema")
 
-		for _, ddl := range strings.Split(k8sMysql.Schema, ";") {
+		for _, ddl := range strings.Split(schema, ";") {
 			if ddl = strings.TrimSpace(ddl); ddl != "" {
 				if _, err := kdb.Exec(ddl); err != nil {
 					klog.Fatal(err)
@@ -209,26 +180,344 @@ func main() {
 		klog.Fatal("IGL_DATABASE: ", err)
 	}
 
+	if cfg.Telemetry.Listen != "" {
+		// Every cluster is expected to be synchronized before the daemon is ready.
+		if len(cfg.Clusters) > 0 {
+			for _, c := range cfg.Clusters {
+				telemetry.DefaultReadiness.Expect(c.Name)
+			}
+		} else {
+			telemetry.DefaultReadiness.Expect(clusterName)
+		}
+
+		g.Go(func() error {
+			return telemetry.Serve(ctx, &cfg.Telemetry, log.WithName("telemetry"), kdb.PingContext)
+		})
+	}
+
+	var clusters []cluster.Config
+	if len(cfg.Clusters) > 0 {
+		clusters = cfg.Clusters
+	} else {
+		clusters = []cluster.Config{{Name: clusterName, Prometheus: cfg.Prometheus}}
+	}
+
+	// The retention of the database is enforced by whichever cluster this daemon leads, as the cleanups span
+	// all clusters and must not depend on the leadership of a particular one.
+	var cleanup *retention
+	if !cfg.Retention.Disabled {
+		cleanup = newRetention(ctx, kdb, &cfg.Retention, log.WithName("retention"))
+	}
+
+	for _, c := range clusters {
+		var kconfig *rest.Config
+		if len(cfg.Clusters) > 0 {
+			kconfig, err = c.ClientConfig()
+			if err != nil {
+				klog.Fatal(err)
+			}
+		} else {
+			kconfig, err = kclientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &overrides).ClientConfig()
+			if err != nil {
+				if kclientcmd.IsEmptyConfig(err) {
+					klog.Fatal(
+						"no configuration provided: set KUBECONFIG environment variable or --kubeconfig CLI flag to" +
+							" a kubeconfig file with cluster access configured")
+				}
+
+				klog.Fatal(errors.Wrap(err, "cannot configure Kubernetes client"))
+			}
+		}
+
+		clientset, err := kubernetes.NewForConfig(kconfig)
+		if err != nil {
+			klog.Fatal(err)
+		}
+
+		metricsClientset, err := kmetrics.NewForConfig(kconfig)
+		if err != nil {
+			klog.Fatal(err)
+		}
+
+		clusterCfg := cfg
+		clusterCfg.Prometheus = c.Prometheus.Inherit(&cfg.Prometheus)
+		if !c.Filter.IsEmpty() {
+			clusterCfg.Filter = c.Filter
+		}
+
+		clusterLog := log.WithValues("cluster", c.Name)
+		g.Go(func() error {
+			return runCluster(ctx, clusterLog, func(ctx context.Context) error {
+				return syncCluster(
+					ctx, c.Name, clientset, metricsClientset, kdb, db, clusterCfg, cleanup, clusterLog, logs)
+			})
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		klog.Fatal(err)
+	}
+}
+
+// runCluster runs the given synchronization of a cluster until the given context is canceled and restarts it
+// with backoff whenever it fails, e.g. because the cluster is unreachable or the lease has been lost,
+// so that the failure of one cluster does not stop the synchronization of the others.
+func runCluster(ctx context.Context, log logr.Logger, sync func(context.Context) error) error {
+	backoffFor := backoff.NewExponentialWithJitter(time.Second, 5*time.Minute)
+
+	for attempt := uint64(1); ; attempt++ {
+		started := time.Now()
+		err := sync(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// A synchronization that has been running for a while succeeded, so its failure starts a new series.
+		if time.Since(started) > 10*time.Minute {
+			attempt = 1
+		}
+
+		delay := backoffFor(attempt)
+		switch {
+		case errors.Is(err, cluster.ErrLeadershipLost):
+			log.Info("Lost leadership, restarting synchronization", "in", delay)
+		case err == nil:
+			log.Info("Synchronization stopped unexpectedly, restarting", "in", delay)
+		default:
+			log.Error(err, "Cannot synchronize cluster, restarting", "in", delay)
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// retention enforces the retention of the database as long as this daemon leads any of its clusters.
+// The cleanups span all clusters, so they run only once per daemon, regardless of how many clusters it leads.
+// Replicas leading different clusters may run them concurrently, which is harmless, as they only delete old rows.
+type retention struct {
+	ctx context.Context
+	kdb *kdatabase.Database
+	cfg *kdatabase.RetentionConfig
+	log logr.Logger
+
+	mu      sync.Mutex
+	leaders int
+	cancel  context.CancelFunc
+}
+
+// newRetention returns a new retention, whose cleanups run until the given context is canceled at the latest.
+func newRetention(
+	ctx context.Context, kdb *kdatabase.Database, cfg *kdatabase.RetentionConfig, log logr.Logger,
+) *retention {
+	return &retention{ctx: ctx, kdb: kdb, cfg: cfg, log: log}
+}
+
+// Lead notes that this daemon leads a cluster and starts the cleanups unless they are already running.
+// The returned function notes the end of the leadership and stops the cleanups once no cluster is led anymore.
+func (r *retention) Lead() (release func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.leaders++
+	if r.leaders == 1 {
+		var ctx context.Context
+		ctx, r.cancel = context.WithCancel(r.ctx)
+
+		go r.run(ctx)
+	}
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+
+			r.leaders--
+			if r.leaders == 0 {
+				r.cancel()
+			}
+		})
+	}
+}
+
+// run performs the cleanups until the given context is canceled and restarts them with backoff whenever they fail.
+func (r *retention) run(ctx context.Context) {
+	backoffFor := backoff.NewExponentialWithJitter(time.Second, 5*time.Minute)
+
+	for attempt := uint64(1); ; attempt++ {
+		err := r.cleanup(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		delay := backoffFor(attempt)
+		r.log.Error(err, "Cannot enforce retention, restarting", "in", delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// cleanup periodically deletes the rows of all time-based tables that are older than their retention.
+func (r *retention) cleanup(ctx context.Context) error {
+	g, ctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		return r.kdb.PeriodicCleanup(ctx, kdatabase.CleanupStmt{
+			Table:  "event",
+			PK:     "uuid",
+			Column: "created",
+		}, r.cfg.DaysFor(kdatabase.RetentionEvents))
+	})
+
+	g.Go(func() error {
+		return r.kdb.PeriodicCleanup(ctx, kdatabase.CleanupStmt{
+			Table:  "prometheus_cluster_metric",
+			PK:     "(cluster_uuid, timestamp, category, name)",
+			Column: "timestamp",
+		}, r.cfg.DaysFor(kdatabase.RetentionClusterMetrics))
+	})
+
+	g.Go(func() error {
+		return r.kdb.PeriodicCleanup(ctx, kdatabase.CleanupStmt{
+			Table:  "prometheus_node_metric",
+			PK:     "(node_uuid, timestamp, category, name)",
+			Column: "timestamp",
+		}, r.cfg.DaysFor(kdatabase.RetentionNodeMetrics))
+	})
+
+	g.Go(func() error {
+		return r.kdb.PeriodicCleanup(ctx, kdatabase.CleanupStmt{
+			Table:  "prometheus_pod_metric",
+			PK:     "(pod_uuid, timestamp, category, name)",
+			Column: "timestamp",
+		}, r.cfg.DaysFor(kdatabase.RetentionPodMetrics))
+	})
+
+	g.Go(func() error {
+		return r.kdb.PeriodicCleanup(ctx, kdatabase.CleanupStmt{
+			Table:  "prometheus_container_metric",
+			PK:     "(container_uuid, timestamp, category, name)",
+			Column: "timestamp",
+		}, r.cfg.DaysFor(kdatabase.RetentionContainerMetrics))
+	})
+
+	g.Go(func() error {
+		return r.kdb.PeriodicCleanup(ctx, kdatabase.CleanupStmt{
+			Table:  "rollout",
+			PK:     "uuid",
+			Column: "start_time",
+		}, r.cfg.DaysFor(kdatabase.RetentionRollouts))
+	})
+
+	return g.Wait()
+}
+
+// syncCluster synchronizes the Kubernetes cluster accessible via clientset and,
+// for the resource usage of pods and nodes without Prometheus, metricsClientset to the database
+// until the given context is canceled or an error occurs.
+// If cleanup is set, the leader also enforces the retention of the database while it leads the cluster.
+func syncCluster(
+	ctx context.Context, name string, clientset *kubernetes.Clientset, metricsClientset *kmetrics.Clientset,
+	kdb *kdatabase.Database, db *database.DB, cfg daemon.Config, cleanup *retention, log logr.Logger,
+	logs *logging.Logging,
+) error {
+	// Everything started by this attempt, e.g. the informers, is stopped once it returns,
+	// as a restarted attempt starts everything anew.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// State rules are applied to all resources with an Icinga state.
+	compiledStateRules, err := rules.NewRules(cfg.StateRules)
+	if err != nil {
+		return err
+	}
+	stateRules := syncv1.WithStateRules(compiledStateRules)
+
+	// The Icinga state of some resources depends on the passage of time, e.g. how long PDBs have not allowed
+	// any disruptions, how long PVCs have been pending, whether CronJobs missed their schedules or
+	// whether rollouts of StatefulSets and DaemonSets stalled,
+	// or on other resources, e.g. the endpoints of services and ingress backends.
+	// Neither causes any updates on its own. Therefore, these resources are resynchronized periodically.
+	resync := map[v1.Object]time.Duration{
+		&kappsv1.DaemonSet{}:             time.Minute,
+		&kappsv1.StatefulSet{}:           time.Minute,
+		&kbatchv1.CronJob{}:              time.Minute,
+		&knetworkingv1.Ingress{}:         time.Minute,
+		&kcorev1.PersistentVolumeClaim{}: 5 * time.Minute,
+		&kpolicyv1.PodDisruptionBudget{}: 5 * time.Minute,
+		&kcorev1.Service{}:               time.Minute,
+	}
+
+	// Metric thresholds are applied to nodes and pods, whose latest metric values are collected from Prometheus or,
+	// without Prometheus, from the metrics API.
+	// As the metrics do not cause any updates either, nodes and pods are then also resynchronized periodically.
+	var samples *metrics.Samples
+	var metricThresholds *metrics.Thresholds
+	if cfg.Prometheus.Thresholds.Enabled() {
+		samples = metrics.NewSamples()
+		metricThresholds = metrics.NewThresholds(cfg.Prometheus.Thresholds, samples)
+		resync[&kcorev1.Node{}] = time.Minute
+		resync[&kcorev1.Pod{}] = time.Minute
+	}
+	thresholds := syncv1.WithThresholds(metricThresholds)
+
+	// Cluster-scoped resources are always watched cluster-wide and only filtered by labels.
+	clusterFactory := informers.NewSharedInformerFactoryWithOptions(
+		clientset, 0,
+		informers.WithCustomResyncConfig(resync),
+		informers.WithTweakListOptions(func(options *v1.ListOptions) {
+			cfg.Filter.TweakListOptions(options, false)
+		}))
+	// Namespaced resources are watched separately for each included namespace, so that
+	// read access to these namespaces is sufficient. Without included namespaces, all namespaces are watched at once.
+	factories := make(map[string]informers.SharedInformerFactory)
+	for _, namespace := range cfg.Filter.Scopes() {
+		factories[namespace] = informers.NewSharedInformerFactoryWithOptions(
+			clientset, 0,
+			informers.WithNamespace(namespace),
+			informers.WithCustomResyncConfig(resync),
+			informers.WithTweakListOptions(func(options *v1.ListOptions) {
+				cfg.Filter.TweakListOptions(options, true)
+			}))
+	}
+	multiplexers := cachev1.NewMultiplexers()
+
 	namespaceName := "kube-system"
-	ns, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespaceName, v1.GetOptions{})
+	ns, err := clientset.CoreV1().Namespaces().Get(ctx, namespaceName, v1.GetOptions{})
 	if err != nil {
-		klog.Fatalf("Failed to retrieve namespace '%s' for cluster '%s': %v", namespaceName, clusterName, err)
+		return errors.Wrapf(err, "cannot retrieve namespace %q of cluster %q", namespaceName, name)
 	}
 
 	clusterInstance := &schemav1.Cluster{
 		Uuid: schemav1.EnsureUUID(ns.UID),
-		Name: schemav1.NewNullableString(clusterName),
+		Name: schemav1.NewNullableString(name),
 	}
 
 	ctx = cluster.NewClusterUuidContext(ctx, clusterInstance.Uuid)
 
 	stmt, _ := kdb.BuildUpsertStmt(clusterInstance)
 	if _, err := kdb.NamedExecContext(ctx, stmt, clusterInstance); err != nil {
-		klog.Error(errors.Wrap(err, "cannot update cluster"))
+		log.Error(err, "cannot update cluster")
 	}
 
-	if _, err := kdb.ExecContext(ctx, "DELETE FROM kubernetes_instance WHERE cluster_uuid = ?", clusterInstance.Uuid); err != nil {
-		klog.Fatal(errors.Wrap(err, "cannot delete instance"))
+	// Without leader election, this is the only instance synchronizing the cluster and
+	// instances of previous runs can be removed. Otherwise, the leader removes stale instances periodically.
+	var leader atomic.Bool
+	if !cfg.LeaderElection.Enabled {
+		leader.Store(true)
+
+		if _, err := kdb.ExecContext(ctx, kdb.Rebind("DELETE FROM kubernetes_instance WHERE cluster_uuid = ?"), clusterInstance.Uuid); err != nil {
+			return errors.Wrap(err, "cannot delete instance")
+		}
 	}
 	// ,omitempty
 	var kubernetesVersion string
@@ -241,6 +530,11 @@ func main() {
 			kubernetesHeartbeat = tick.Time
 		}
 
+		role := schemav1.InstanceRoleFollower
+		if leader.Load() {
+			role = schemav1.InstanceRoleLeader
+		}
+
 		instance := schemav1.Instance{
 			Uuid:                instanceId[:],
 			ClusterUuid:         clusterInstance.Uuid,
@@ -252,346 +546,711 @@ func main() {
 				Valid: true,
 			},
 			Message:   schemav1.NewNullableString(err),
+			Role:      role,
 			Heartbeat: types.UnixMilli(tick.Time),
 		}
 
 		stmt, _ := kdb.BuildUpsertStmt(instance)
 
 		if _, err := kdb.NamedExecContext(ctx, stmt, instance); err != nil {
-			klog.Error(errors.Wrap(err, "cannot update instance"))
+			log.Error(err, "cannot update instance")
+		}
+
+		if cfg.LeaderElection.Enabled && leader.Load() {
+			if _, err := kdb.ExecContext(
+				ctx,
+				kdb.Rebind("DELETE FROM kubernetes_instance WHERE cluster_uuid = ? AND heartbeat < ?"),
+				clusterInstance.Uuid, types.UnixMilli(tick.Time.Add(-staleInstanceTimeout)),
+			); err != nil {
+				log.Error(err, "cannot delete stale instances")
+			}
 		}
 	}, periodic.Immediate()).Stop()
 
+	if cfg.LeaderElection.Enabled {
+		// Followers keep their informer caches warm, so that they can take over immediately.
+		warmUpInformers(ctx, clusterFactory, factories, &cfg.Filter)
+
+		ctx, err = cluster.AwaitLeadership(ctx, clientset, &cfg.LeaderElection, instanceId.String(), log)
+		if err != nil {
+			return err
+		}
+
+		leader.Store(true)
+		log.Info("Acquired leadership")
+	}
+
+	// The cluster is ready once the informers of all its synchronizations have synced.
+	synced := make(map[string]kcache.InformerSynced)
+	for name, informer := range syncedInformers(clusterFactory, factories, &cfg.Filter) {
+		synced[name] = informer.HasSynced
+	}
+	defer telemetry.DefaultReadiness.Register(name, synced)()
+
+	g, ctx := errgroup.WithContext(ctx)
+
 	if err := internal.SyncNotificationsConfig(ctx, db, &cfg.Notifications, clusterInstance.Uuid); err != nil {
-		klog.Fatal(err)
+		return err
+	}
+
+	if err := internal.SyncRetentionConfig(ctx, db, &cfg.Retention, clusterInstance.Uuid); err != nil {
+		return err
+	}
+
+	if cleanup != nil {
+		defer cleanup.Lead()()
+	}
+
+	// Rollouts that have not ended yet are continued, so that their start survives restarts.
+	rolloutTracker := syncv1.NewRollouts(kdb, clusterInstance.Uuid)
+	if err := rolloutTracker.Restore(ctx); err != nil {
+		return err
 	}
+	rollouts := syncv1.WithRollouts(rolloutTracker)
 
-	if cfg.Notifications.Url != "" {
-		klog.Infof("Sending notifications to %s", cfg.Notifications.Url)
+	if cfg.Notifications.Enabled() {
+		var outbox *notifications.Outbox
+		if cfg.Notifications.Outbox {
+			outbox = notifications.NewOutbox(kdb, clusterInstance.Uuid)
+		}
 
-		nclient, err := notifications.NewClient("icinga-kubernetes/"+internal.Version.Version, cfg.Notifications)
+		nclient, err := notifications.NewClient(
+			"icinga-kubernetes/"+internal.Version.Version, name, cfg.Notifications, outbox,
+			notifications.NewSilenceStore(kdb, clusterInstance.Uuid))
 		if err != nil {
-			klog.Fatal(err)
+			return err
+		}
+
+		log.Info("Sending notifications", "sinks", nclient.Sinks())
+
+		if err := internal.RestoreNotifiedStates(
+			ctx, db, nclient, clusterInstance.Uuid,
+			&schemav1.CronJob{},
+			&schemav1.DaemonSet{},
+			&schemav1.Deployment{},
+			&schemav1.Hpa{},
+			&schemav1.Ingress{},
+			&schemav1.Job{},
+			&schemav1.Namespace{},
+			&schemav1.Node{},
+			&schemav1.Pdb{},
+			&schemav1.PersistentVolume{},
+			&schemav1.Pod{},
+			&schemav1.Pvc{},
+			&schemav1.ReplicaSet{},
+			&schemav1.Service{},
+			&schemav1.StatefulSet{},
+		); err != nil {
+			return err
+		}
+
+		if err := nclient.RestoreSilences(ctx); err != nil {
+			return err
 		}
 
 		g.Go(func() error {
-			return nclient.Stream(ctx, cachev1.Multiplexers().Nodes().UpsertEvents().Out())
+			return nclient.Deliver(ctx)
 		})
 
 		g.Go(func() error {
-			return nclient.Stream(ctx, cachev1.Multiplexers().DaemonSets().UpsertEvents().Out())
+			return nclient.Stream(ctx, multiplexers.Nodes().UpsertEvents().Out(), multiplexers.Nodes().DeleteEvents().Out())
 		})
 
 		g.Go(func() error {
-			return nclient.Stream(ctx, cachev1.Multiplexers().StatefulSets().UpsertEvents().Out())
+			return nclient.Stream(ctx, multiplexers.DaemonSets().UpsertEvents().Out(), multiplexers.DaemonSets().DeleteEvents().Out())
 		})
 
 		g.Go(func() error {
-			return nclient.Stream(ctx, cachev1.Multiplexers().Deployments().UpsertEvents().Out())
+			return nclient.Stream(ctx, multiplexers.StatefulSets().UpsertEvents().Out(), multiplexers.StatefulSets().DeleteEvents().Out())
 		})
 
 		g.Go(func() error {
-			return nclient.Stream(ctx, cachev1.Multiplexers().ReplicaSets().UpsertEvents().Out())
+			return nclient.Stream(ctx, multiplexers.Deployments().UpsertEvents().Out(), multiplexers.Deployments().DeleteEvents().Out())
 		})
 
 		g.Go(func() error {
-			return nclient.Stream(ctx, cachev1.Multiplexers().Pods().UpsertEvents().Out())
+			return nclient.Stream(ctx, multiplexers.ReplicaSets().UpsertEvents().Out(), multiplexers.ReplicaSets().DeleteEvents().Out())
 		})
-	}
 
-	g.Go(func() error {
-		return SyncServicePods(ctx, kdb, factory.Core().V1().Services(), factory.Core().V1().Pods())
-	})
+		g.Go(func() error {
+			return nclient.Stream(ctx, multiplexers.Hpas().UpsertEvents().Out(), multiplexers.Hpas().DeleteEvents().Out())
+		})
+
+		g.Go(func() error {
+			return nclient.Stream(ctx, multiplexers.Pdbs().UpsertEvents().Out(), multiplexers.Pdbs().DeleteEvents().Out())
+		})
+
+		g.Go(func() error {
+			return nclient.Stream(ctx, multiplexers.Pvcs().UpsertEvents().Out(), multiplexers.Pvcs().DeleteEvents().Out())
+		})
+
+		g.Go(func() error {
+			return nclient.Stream(ctx, multiplexers.PersistentVolumes().UpsertEvents().Out(), multiplexers.PersistentVolumes().DeleteEvents().Out())
+		})
+
+		g.Go(func() error {
+			return nclient.Stream(ctx, multiplexers.CronJobs().UpsertEvents().Out(), multiplexers.CronJobs().DeleteEvents().Out())
+		})
+
+		g.Go(func() error {
+			return nclient.Stream(ctx, multiplexers.Services().UpsertEvents().Out(), multiplexers.Services().DeleteEvents().Out())
+		})
+
+		g.Go(func() error {
+			return nclient.Stream(ctx, multiplexers.Ingresses().UpsertEvents().Out(), multiplexers.Ingresses().DeleteEvents().Out())
+		})
+
+		g.Go(func() error {
+			return nclient.Stream(ctx, multiplexers.Jobs().UpsertEvents().Out(), multiplexers.Jobs().DeleteEvents().Out())
+		})
+
+		g.Go(func() error {
+			return nclient.Stream(ctx, multiplexers.Namespaces().UpsertEvents().Out(), multiplexers.Namespaces().DeleteEvents().Out())
+		})
+
+		g.Go(func() error {
+			return nclient.Stream(ctx, multiplexers.Pods().UpsertEvents().Out(), multiplexers.Pods().DeleteEvents().Out())
+		})
+	}
 
 	err = internal.SyncPrometheusConfig(ctx, db, &cfg.Prometheus, clusterInstance.Uuid)
 	if err != nil {
-		klog.Error(errors.Wrap(err, "cannot sync prometheus config"))
+		log.Error(err, "cannot sync prometheus config")
 	}
 
 	if cfg.Prometheus.Url == "" {
 		err = internal.AutoDetectPrometheus(ctx, clientset, &cfg.Prometheus)
 		if err != nil {
-			klog.Error(errors.Wrap(err, "cannot auto-detect prometheus"))
+			log.Error(err, "cannot auto-detect prometheus")
 		}
 	}
 
+	var promMetricSync *metrics.PromMetricSync
 	if cfg.Prometheus.Url != "" {
-		var basicAuthTransport http.RoundTripper
+		promApiClient, err := metrics.NewPromApiClient(&cfg.Prometheus)
+		if err != nil {
+			return err
+		}
 
-		if cfg.Prometheus.Username != "" && cfg.Prometheus.Password != "" {
-			basicAuthTransport = &com.BasicAuthTransport{
-				RoundTripper: http.DefaultTransport,
-				Username:     cfg.Prometheus.Username,
-				Password:     cfg.Prometheus.Password,
-			}
+		promMetricSync = metrics.NewPromMetricSync(
+			promApiClient, db, clusterInstance.Uuid, cfg.Prometheus.Queries, samples,
+			logs.GetChildLogger("prometheus"))
+
+		if cfg.Prometheus.Enabled(metrics.CategoryCluster) {
+			g.Go(func() error {
+				return promMetricSync.Clusters(ctx)
+			})
 		}
 
-		promClient, err := promapi.NewClient(promapi.Config{
-			Address:      cfg.Prometheus.Url,
-			RoundTripper: basicAuthTransport,
-		})
+		if cfg.Prometheus.Enabled(metrics.CategoryNode) {
+			g.Go(func() error {
+				return promMetricSync.Nodes(ctx, clusterFactory.Core().V1().Nodes().Informer())
+			})
+		}
+	}
+
+	// Without Prometheus, the resource usage of pods and nodes is synchronized from the metrics API, if available.
+	var metricsServerSync *metrics.MetricsServerSync
+	if promMetricSync == nil {
+		available, err := metrics.MetricsApiAvailable(clientset.Discovery())
 		if err != nil {
-			klog.Fatal(errors.Wrap(err, "error creating Prometheus client"))
+			log.Error(err, "cannot detect metrics API")
 		}
 
-		promApiClient := promv1.NewAPI(promClient)
-		promMetricSync := metrics.NewPromMetricSync(promApiClient, db, logs.GetChildLogger("prometheus"))
+		if available {
+			log.Info("Synchronizing resource usage from the metrics API")
 
-		g.Go(func() error {
-			return promMetricSync.Nodes(ctx, factory.Core().V1().Nodes().Informer())
-		})
+			metricsServerSync = metrics.NewMetricsServerSync(
+				metricsClientset, db, clusterInstance.Uuid, samples, logs.GetChildLogger("metrics-server"))
 
-		g.Go(func() error {
-			return promMetricSync.Pods(ctx, factory.Core().V1().Pods().Informer())
-		})
+			g.Go(func() error {
+				return metricsServerSync.Nodes(
+					ctx, cfg.Filter.LabelSelector, clusterFactory.Core().V1().Nodes().Informer().GetStore())
+			})
+		}
 	}
 
-	g.Go(func() error {
-		s := syncv1.NewSync(kdb, factory.Core().V1().Namespaces().Informer(), log.WithName("namespaces"), schemav1.NewNamespace)
-
-		return s.Run(ctx)
-	})
+	if metricThresholds != nil && promMetricSync == nil && metricsServerSync == nil {
+		log.Error(errors.New("neither Prometheus nor the metrics API is available"), "Cannot evaluate metric thresholds")
+	}
 
 	wg := sync.WaitGroup{}
 
 	wg.Add(1)
 	g.Go(func() error {
-		s := syncv1.NewSync(kdb, factory.Core().V1().Nodes().Informer(), log.WithName("nodes"), schemav1.NewNode)
+		s := syncv1.NewSync(
+			kdb, namespaceInformer(clusterFactory, &cfg.Filter), log.WithName("namespaces"), schemav1.NewNamespace)
 
 		var forwardForNotifications []syncv1.Feature
-		if cfg.Notifications.Url != "" {
+		if cfg.Notifications.Enabled() {
 			forwardForNotifications = append(
 				forwardForNotifications,
-				syncv1.WithOnUpsert(database.OnSuccessSendTo(cachev1.Multiplexers().Nodes().UpsertEvents().In())),
-				syncv1.WithOnDelete(database.OnSuccessSendTo(cachev1.Multiplexers().Nodes().DeleteEvents().In())),
+				syncv1.WithOnUpsert(database.OnSuccessSendTo(multiplexers.Namespaces().UpsertEvents().In())),
+				syncv1.WithOnDelete(database.OnSuccessSendTo(multiplexers.Namespaces().DeleteEvents().In())),
 			)
 		}
 
 		wg.Done()
 
-		return s.Run(ctx, forwardForNotifications...)
+		return s.Run(ctx, append(forwardForNotifications, stateRules)...)
 	})
 
 	wg.Add(1)
 	g.Go(func() error {
-		schemav1.SyncContainers(
-			ctx,
-			kdb,
-			g,
-			cachev1.Multiplexers().Pods().UpsertEvents().Out(),
-			cachev1.Multiplexers().Pods().DeleteEvents().Out(),
-		)
-
-		f := schemav1.NewPodFactory(clientset)
-		s := syncv1.NewSync(kdb, factory.Core().V1().Pods().Informer(), log.WithName("pods"), f.New)
-
-		wg.Done()
-
-		return s.Run(
-			ctx,
-			syncv1.WithOnUpsert(database.OnSuccessSendTo(cachev1.Multiplexers().Pods().UpsertEvents().In())),
-			syncv1.WithOnDelete(database.OnSuccessSendTo(cachev1.Multiplexers().Pods().DeleteEvents().In())),
-		)
-	})
-
-	wg.Add(1)
-	g.Go(func() error {
-		s := syncv1.NewSync(
-			kdb, factory.Apps().V1().Deployments().Informer(), log.WithName("deployments"), schemav1.NewDeployment)
+		s := syncv1.NewSync(kdb, clusterFactory.Core().V1().Nodes().Informer(), log.WithName("nodes"), schemav1.NewNode)
 
 		var forwardForNotifications []syncv1.Feature
-		if cfg.Notifications.Url != "" {
+		if cfg.Notifications.Enabled() {
 			forwardForNotifications = append(
 				forwardForNotifications,
-				syncv1.WithOnUpsert(database.OnSuccessSendTo(cachev1.Multiplexers().Deployments().UpsertEvents().In())),
-				syncv1.WithOnDelete(database.OnSuccessSendTo(cachev1.Multiplexers().Deployments().DeleteEvents().In())),
+				syncv1.WithOnUpsert(database.OnSuccessSendTo(multiplexers.Nodes().UpsertEvents().In())),
+				syncv1.WithOnDelete(database.OnSuccessSendTo(multiplexers.Nodes().DeleteEvents().In())),
 			)
 		}
 
 		wg.Done()
 
-		return s.Run(ctx, forwardForNotifications...)
+		return s.Run(ctx, append(forwardForNotifications, thresholds, stateRules)...)
 	})
 
 	wg.Add(1)
 	g.Go(func() error {
-		s := syncv1.NewSync(
-			kdb, factory.Apps().V1().DaemonSets().Informer(), log.WithName("daemon-sets"), schemav1.NewDaemonSet)
+		s := syncv1.NewSync(kdb, clusterFactory.Core().V1().PersistentVolumes().Informer(), log.WithName("persistent-volumes"), schemav1.NewPersistentVolume)
 
 		var forwardForNotifications []syncv1.Feature
-		if cfg.Notifications.Url != "" {
+		if cfg.Notifications.Enabled() {
 			forwardForNotifications = append(
 				forwardForNotifications,
-				syncv1.WithOnUpsert(database.OnSuccessSendTo(cachev1.Multiplexers().DaemonSets().UpsertEvents().In())),
-				syncv1.WithOnDelete(database.OnSuccessSendTo(cachev1.Multiplexers().DaemonSets().DeleteEvents().In())),
+				syncv1.WithOnUpsert(database.OnSuccessSendTo(multiplexers.PersistentVolumes().UpsertEvents().In())),
+				syncv1.WithOnDelete(database.OnSuccessSendTo(multiplexers.PersistentVolumes().DeleteEvents().In())),
 			)
 		}
 
 		wg.Done()
 
-		return s.Run(ctx, forwardForNotifications...)
+		return s.Run(ctx, append(forwardForNotifications, stateRules)...)
 	})
 
-	wg.Add(1)
-	g.Go(func() error {
-		s := syncv1.NewSync(
-			kdb, factory.Apps().V1().ReplicaSets().Informer(), log.WithName("replica-sets"), schemav1.NewReplicaSet)
+	// Storage classes are not synchronized, but looked up to determine whether PVCs wait for their first consumer.
+	// The informer may already be running, e.g. to keep the caches of leader election followers warm.
+	storageClasses := clusterFactory.Storage().V1().StorageClasses()
+	if !storageClasses.Informer().HasSynced() {
+		go storageClasses.Informer().Run(ctx.Done())
+	}
 
-		var forwardForNotifications []syncv1.Feature
-		if cfg.Notifications.Url != "" {
-			forwardForNotifications = append(
-				forwardForNotifications,
-				syncv1.WithOnUpsert(database.OnSuccessSendTo(cachev1.Multiplexers().ReplicaSets().UpsertEvents().In())),
-				syncv1.WithOnDelete(database.OnSuccessSendTo(cachev1.Multiplexers().ReplicaSets().DeleteEvents().In())),
-			)
+	schemav1.SyncContainers(
+		ctx,
+		kdb,
+		g,
+		multiplexers.Pods().UpsertEvents().Out(),
+		multiplexers.Pods().DeleteEvents().Out(),
+	)
+
+	for namespace, factory := range factories {
+		// Database rows that do not belong to any watched namespace are also part of
+		// the warmup of a namespace, so that they are deleted once its informer has synced.
+		warmup := syncv1.WithWarmupNamespaces(cfg.Filter.WarmupNamespaces(namespace))
+
+		log := log
+		if namespace != v1.NamespaceAll {
+			log = log.WithValues("namespace", namespace)
 		}
 
-		wg.Done()
+		g.Go(func() error {
+			return SyncServicePods(ctx, kdb, multiplexers, factory.Core().V1().Services(), factory.Core().V1().Pods())
+		})
 
-		return s.Run(ctx, forwardForNotifications...)
-	})
+		g.Go(func() error {
+			return SyncPdbPods(ctx, kdb, multiplexers, namespace, factory.Policy().V1().PodDisruptionBudgets(), factory.Core().V1().Pods())
+		})
 
-	wg.Add(1)
-	g.Go(func() error {
-		s := syncv1.NewSync(
-			kdb, factory.Apps().V1().StatefulSets().Informer(), log.WithName("stateful-sets"), schemav1.NewStatefulSet)
+		if promMetricSync != nil && cfg.Prometheus.Enabled(metrics.CategoryPod) {
+			g.Go(func() error {
+				return promMetricSync.Pods(ctx, factory.Core().V1().Pods().Informer())
+			})
+		}
 
-		var forwardForNotifications []syncv1.Feature
-		if cfg.Notifications.Url != "" {
-			forwardForNotifications = append(
-				forwardForNotifications,
-				syncv1.WithOnUpsert(database.OnSuccessSendTo(cachev1.Multiplexers().StatefulSets().UpsertEvents().In())),
-				syncv1.WithOnDelete(database.OnSuccessSendTo(cachev1.Multiplexers().StatefulSets().DeleteEvents().In())),
-			)
+		if promMetricSync != nil && cfg.Prometheus.Enabled(metrics.CategoryContainer) {
+			g.Go(func() error {
+				return promMetricSync.Containers(ctx, factory.Core().V1().Pods().Informer())
+			})
 		}
 
-		wg.Done()
+		if metricsServerSync != nil {
+			g.Go(func() error {
+				return metricsServerSync.Pods(
+					ctx, namespace, cfg.Filter.LabelSelector, cfg.Filter.ExcludeNamespaces,
+					factory.Core().V1().Pods().Informer().GetStore())
+			})
+		}
 
-		return s.Run(ctx, forwardForNotifications...)
-	})
+		wg.Add(1)
+		g.Go(func() error {
+			f := schemav1.NewPodFactory(clientset)
+			s := syncv1.NewSync(kdb, factory.Core().V1().Pods().Informer(), log.WithName("pods"), f.New)
 
-	g.Go(func() error {
-		f := schemav1.NewServiceFactory(clientset)
-		s := syncv1.NewSync(kdb, factory.Core().V1().Services().Informer(), log.WithName("services"), f.NewService)
+			wg.Done()
 
-		return s.Run(
-			ctx,
-			syncv1.WithOnUpsert(database.OnSuccessSendTo(cachev1.Multiplexers().Services().UpsertEvents().In())),
-		)
-	})
+			return s.Run(
+				ctx,
+				syncv1.WithOnUpsert(database.OnSuccessSendTo(multiplexers.Pods().UpsertEvents().In())),
+				syncv1.WithOnDelete(database.OnSuccessSendTo(multiplexers.Pods().DeleteEvents().In())),
+				warmup,
+				thresholds,
+				stateRules,
+			)
+		})
 
-	g.Go(func() error {
-		s := syncv1.NewSync(kdb, factory.Discovery().V1().EndpointSlices().Informer(), log.WithName("endpoints"), schemav1.NewEndpointSlice)
+		wg.Add(1)
+		g.Go(func() error {
+			s := syncv1.NewSync(
+				kdb, factory.Apps().V1().Deployments().Informer(), log.WithName("deployments"), schemav1.NewDeployment)
+
+			var forwardForNotifications []syncv1.Feature
+			if cfg.Notifications.Enabled() {
+				forwardForNotifications = append(
+					forwardForNotifications,
+					syncv1.WithOnUpsert(database.OnSuccessSendTo(multiplexers.Deployments().UpsertEvents().In())),
+					syncv1.WithOnDelete(database.OnSuccessSendTo(multiplexers.Deployments().DeleteEvents().In())),
+				)
+			}
 
-		return s.Run(ctx)
-	})
+			wg.Done()
 
-	g.Go(func() error {
-		s := syncv1.NewSync(kdb, factory.Core().V1().Secrets().Informer(), log.WithName("secrets"), schemav1.NewSecret)
-		return s.Run(ctx)
-	})
+			return s.Run(ctx, append(forwardForNotifications, warmup, stateRules, rollouts)...)
+		})
 
-	g.Go(func() error {
-		s := syncv1.NewSync(kdb, factory.Core().V1().ConfigMaps().Informer(), log.WithName("config-maps"), schemav1.NewConfigMap)
+		wg.Add(1)
+		g.Go(func() error {
+			s := syncv1.NewSync(
+				kdb, factory.Apps().V1().DaemonSets().Informer(), log.WithName("daemon-sets"), schemav1.NewDaemonSet)
+
+			var forwardForNotifications []syncv1.Feature
+			if cfg.Notifications.Enabled() {
+				forwardForNotifications = append(
+					forwardForNotifications,
+					syncv1.WithOnUpsert(database.OnSuccessSendTo(multiplexers.DaemonSets().UpsertEvents().In())),
+					syncv1.WithOnDelete(database.OnSuccessSendTo(multiplexers.DaemonSets().DeleteEvents().In())),
+				)
+			}
 
-		return s.Run(ctx)
-	})
+			wg.Done()
 
-	g.Go(func() error {
-		s := syncv1.NewSync(kdb, factory.Events().V1().Events().Informer(), log.WithName("events"), schemav1.NewEvent)
+			return s.Run(ctx, append(forwardForNotifications, warmup, stateRules, rollouts)...)
+		})
 
-		return s.Run(ctx, syncv1.WithNoDelete(), syncv1.WithNoWarumup())
-	})
+		wg.Add(1)
+		g.Go(func() error {
+			s := syncv1.NewSync(
+				kdb, factory.Apps().V1().ReplicaSets().Informer(), log.WithName("replica-sets"), schemav1.NewReplicaSet)
+
+			var forwardForNotifications []syncv1.Feature
+			if cfg.Notifications.Enabled() {
+				forwardForNotifications = append(
+					forwardForNotifications,
+					syncv1.WithOnUpsert(database.OnSuccessSendTo(multiplexers.ReplicaSets().UpsertEvents().In())),
+					syncv1.WithOnDelete(database.OnSuccessSendTo(multiplexers.ReplicaSets().DeleteEvents().In())),
+				)
+			}
 
-	g.Go(func() error {
-		s := syncv1.NewSync(kdb, factory.Core().V1().PersistentVolumeClaims().Informer(), log.WithName("pvcs"), schemav1.NewPvc)
+			wg.Done()
 
-		return s.Run(ctx)
-	})
+			return s.Run(ctx, append(forwardForNotifications, warmup, stateRules)...)
+		})
 
-	g.Go(func() error {
-		s := syncv1.NewSync(kdb, factory.Core().V1().PersistentVolumes().Informer(), log.WithName("persistent-volumes"), schemav1.NewPersistentVolume)
+		wg.Add(1)
+		g.Go(func() error {
+			s := syncv1.NewSync(
+				kdb, factory.Apps().V1().StatefulSets().Informer(), log.WithName("stateful-sets"), schemav1.NewStatefulSet)
+
+			var forwardForNotifications []syncv1.Feature
+			if cfg.Notifications.Enabled() {
+				forwardForNotifications = append(
+					forwardForNotifications,
+					syncv1.WithOnUpsert(database.OnSuccessSendTo(multiplexers.StatefulSets().UpsertEvents().In())),
+					syncv1.WithOnDelete(database.OnSuccessSendTo(multiplexers.StatefulSets().DeleteEvents().In())),
+				)
+			}
 
-		return s.Run(ctx)
-	})
+			wg.Done()
 
-	g.Go(func() error {
-		s := syncv1.NewSync(kdb, factory.Batch().V1().Jobs().Informer(), log.WithName("jobs"), schemav1.NewJob)
+			return s.Run(ctx, append(forwardForNotifications, warmup, stateRules, rollouts)...)
+		})
 
-		return s.Run(ctx)
-	})
+		wg.Add(1)
+		g.Go(func() error {
+			s := syncv1.NewSync(
+				kdb, factory.Autoscaling().V2().HorizontalPodAutoscalers().Informer(), log.WithName("hpas"), schemav1.NewHpa)
+
+			var forwardForNotifications []syncv1.Feature
+			if cfg.Notifications.Enabled() {
+				forwardForNotifications = append(
+					forwardForNotifications,
+					syncv1.WithOnUpsert(database.OnSuccessSendTo(multiplexers.Hpas().UpsertEvents().In())),
+					syncv1.WithOnDelete(database.OnSuccessSendTo(multiplexers.Hpas().DeleteEvents().In())),
+				)
+			}
 
-	g.Go(func() error {
-		s := syncv1.NewSync(kdb, factory.Batch().V1().CronJobs().Informer(), log.WithName("cron-jobs"), schemav1.NewCronJob)
+			wg.Done()
 
-		return s.Run(ctx)
-	})
+			return s.Run(ctx, append(forwardForNotifications, warmup, stateRules)...)
+		})
 
-	g.Go(func() error {
-		s := syncv1.NewSync(kdb, factory.Networking().V1().Ingresses().Informer(), log.WithName("ingresses"), schemav1.NewIngress)
+		wg.Add(1)
+		g.Go(func() error {
+			s := syncv1.NewSync(
+				kdb, factory.Policy().V1().PodDisruptionBudgets().Informer(), log.WithName("pdbs"), schemav1.NewPdb)
 
-		return s.Run(ctx)
-	})
+			wg.Done()
 
-	g.Go(func() error {
-		wg.Wait()
+			return s.Run(
+				ctx,
+				syncv1.WithOnUpsert(database.OnSuccessSendTo(multiplexers.Pdbs().UpsertEvents().In())),
+				syncv1.WithOnDelete(database.OnSuccessSendTo(multiplexers.Pdbs().DeleteEvents().In())),
+				warmup,
+				stateRules,
+			)
+		})
 
-		klog.V(2).Info("Starting multiplexers")
+		g.Go(func() error {
+			// The state of services depends on their endpoints, which must not be evaluated from an empty cache.
+			endpointSlices := factory.Discovery().V1().EndpointSlices()
+			if !kcache.WaitForCacheSync(ctx.Done(), endpointSlices.Informer().HasSynced) {
+				return errors.New("timed out waiting for endpoint slice cache to sync")
+			}
 
-		return cachev1.Multiplexers().Run(ctx)
-	})
+			f := schemav1.NewServiceFactory(clientset, endpointSlices.Lister())
+			s := syncv1.NewSync(kdb, factory.Core().V1().Services().Informer(), log.WithName("services"), f.NewService)
 
-	g.Go(func() error {
-		return kdb.PeriodicCleanup(ctx, kdatabase.CleanupStmt{
-			Table:  "event",
-			PK:     "uuid",
-			Column: "created",
+			return s.Run(
+				ctx,
+				syncv1.WithOnUpsert(database.OnSuccessSendTo(multiplexers.Services().UpsertEvents().In())),
+				syncv1.WithOnDelete(database.OnSuccessSendTo(multiplexers.Services().DeleteEvents().In())),
+				warmup,
+				stateRules,
+			)
 		})
-	})
 
-	g.Go(func() error {
-		return kdb.PeriodicCleanup(ctx, kdatabase.CleanupStmt{
-			Table:  "prometheus_cluster_metric",
-			PK:     "(cluster_uuid, timestamp, category, name)",
-			Column: "timestamp",
+		g.Go(func() error {
+			s := syncv1.NewSync(kdb, factory.Discovery().V1().EndpointSlices().Informer(), log.WithName("endpoints"), schemav1.NewEndpointSlice)
+
+			return s.Run(ctx, warmup)
 		})
-	})
 
-	g.Go(func() error {
-		return kdb.PeriodicCleanup(ctx, kdatabase.CleanupStmt{
-			Table:  "prometheus_node_metric",
-			PK:     "(node_uuid, timestamp, category, name)",
-			Column: "timestamp",
+		g.Go(func() error {
+			s := syncv1.NewSync(kdb, factory.Core().V1().Secrets().Informer(), log.WithName("secrets"), schemav1.NewSecret)
+			return s.Run(ctx, warmup)
 		})
-	})
 
-	g.Go(func() error {
-		return kdb.PeriodicCleanup(ctx, kdatabase.CleanupStmt{
-			Table:  "prometheus_pod_metric",
-			PK:     "(pod_uuid, timestamp, category, name)",
-			Column: "timestamp",
+		g.Go(func() error {
+			s := syncv1.NewSync(kdb, factory.Core().V1().ConfigMaps().Informer(), log.WithName("config-maps"), schemav1.NewConfigMap)
+
+			return s.Run(ctx, warmup)
 		})
-	})
 
-	g.Go(func() error {
-		return kdb.PeriodicCleanup(ctx, kdatabase.CleanupStmt{
-			Table:  "prometheus_container_metric",
-			PK:     "(container_uuid, timestamp, category, name)",
-			Column: "timestamp",
+		g.Go(func() error {
+			s := syncv1.NewSync(kdb, factory.Events().V1().Events().Informer(), log.WithName("events"), schemav1.NewEvent)
+
+			return s.Run(ctx, syncv1.WithNoDelete(), syncv1.WithNoWarumup())
+		})
+
+		wg.Add(1)
+		g.Go(func() error {
+			if !kcache.WaitForCacheSync(ctx.Done(), storageClasses.Informer().HasSynced) {
+				return errors.New("timed out waiting for storage class cache to sync")
+			}
+
+			f := schemav1.NewPvcFactory(storageClasses.Lister())
+			s := syncv1.NewSync(kdb, factory.Core().V1().PersistentVolumeClaims().Informer(), log.WithName("pvcs"), f.NewPvc)
+
+			var forwardForNotifications []syncv1.Feature
+			if cfg.Notifications.Enabled() {
+				forwardForNotifications = append(
+					forwardForNotifications,
+					syncv1.WithOnUpsert(database.OnSuccessSendTo(multiplexers.Pvcs().UpsertEvents().In())),
+					syncv1.WithOnDelete(database.OnSuccessSendTo(multiplexers.Pvcs().DeleteEvents().In())),
+				)
+			}
+
+			wg.Done()
+
+			return s.Run(ctx, append(forwardForNotifications, warmup, stateRules)...)
+		})
+
+		wg.Add(1)
+		g.Go(func() error {
+			s := syncv1.NewSync(kdb, factory.Batch().V1().Jobs().Informer(), log.WithName("jobs"), schemav1.NewJob)
+
+			var forwardForNotifications []syncv1.Feature
+			if cfg.Notifications.Enabled() {
+				forwardForNotifications = append(
+					forwardForNotifications,
+					syncv1.WithOnUpsert(database.OnSuccessSendTo(multiplexers.Jobs().UpsertEvents().In())),
+					syncv1.WithOnDelete(database.OnSuccessSendTo(multiplexers.Jobs().DeleteEvents().In())),
+				)
+			}
+
+			wg.Done()
+
+			return s.Run(ctx, append(forwardForNotifications, warmup, stateRules)...)
+		})
+
+		wg.Add(1)
+		g.Go(func() error {
+			jobs := factory.Batch().V1().Jobs().Informer()
+			if err := jobs.AddIndexers(kcache.Indexers{schemav1.JobOwnerIndex: schemav1.JobOwnerIndexFunc}); err != nil {
+				return errors.Wrap(err, "cannot add index of jobs by owner")
+			}
+
+			f := schemav1.NewCronJobFactory(jobs.GetIndexer())
+			s := syncv1.NewSync(kdb, factory.Batch().V1().CronJobs().Informer(), log.WithName("cron-jobs"), f.NewCronJob)
+
+			var forwardForNotifications []syncv1.Feature
+			if cfg.Notifications.Enabled() {
+				forwardForNotifications = append(
+					forwardForNotifications,
+					syncv1.WithOnUpsert(database.OnSuccessSendTo(multiplexers.CronJobs().UpsertEvents().In())),
+					syncv1.WithOnDelete(database.OnSuccessSendTo(multiplexers.CronJobs().DeleteEvents().In())),
+				)
+			}
+
+			wg.Done()
+
+			return s.Run(ctx, append(forwardForNotifications, warmup, stateRules)...)
+		})
+
+		wg.Add(1)
+		g.Go(func() error {
+			// The state of ingresses depends on their backend services and their endpoints,
+			// which must not be evaluated from empty caches.
+			services, endpointSlices := factory.Core().V1().Services(), factory.Discovery().V1().EndpointSlices()
+			if !kcache.WaitForCacheSync(
+				ctx.Done(), services.Informer().HasSynced, endpointSlices.Informer().HasSynced) {
+				return errors.New("timed out waiting for service and endpoint slice caches to sync")
+			}
+
+			f := schemav1.NewIngressFactory(services.Lister(), endpointSlices.Lister())
+			s := syncv1.NewSync(kdb, factory.Networking().V1().Ingresses().Informer(), log.WithName("ingresses"), f.NewIngress)
+
+			var forwardForNotifications []syncv1.Feature
+			if cfg.Notifications.Enabled() {
+				forwardForNotifications = append(
+					forwardForNotifications,
+					syncv1.WithOnUpsert(database.OnSuccessSendTo(multiplexers.Ingresses().UpsertEvents().In())),
+					syncv1.WithOnDelete(database.OnSuccessSendTo(multiplexers.Ingresses().DeleteEvents().In())),
+				)
+			}
+
+			wg.Done()
+
+			return s.Run(ctx, append(forwardForNotifications, warmup, stateRules)...)
 		})
+	}
+
+	g.Go(func() error {
+		wg.Wait()
+
+		log.V(2).Info("Starting multiplexers")
+
+		return multiplexers.Run(ctx)
 	})
 
 	if err := g.Wait(); err != nil {
-		klog.Fatal(err)
+		if cause := context.Cause(ctx); errors.Is(cause, cluster.ErrLeadershipLost) {
+			return cause
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// namespaceInformer returns the informer of namespaces, which are filtered by name instead of namespace.
+func namespaceInformer(factory informers.SharedInformerFactory, filter *cluster.FilterConfig) kcache.SharedIndexInformer {
+	return factory.InformerFor(
+		&kcorev1.Namespace{},
+		func(clientset kubernetes.Interface, resync time.Duration) kcache.SharedIndexInformer {
+			return v2.NewFilteredNamespaceInformer(
+				clientset, resync, kcache.Indexers{}, filter.TweakNamespaceListOptions)
+		})
+}
+
+// syncedInformers returns the informers of all synchronized resources by name,
+// which is prefixed with the namespace for namespaced resources watched per namespace.
+func syncedInformers(
+	clusterFactory informers.SharedInformerFactory,
+	factories map[string]informers.SharedInformerFactory, filter *cluster.FilterConfig,
+) map[string]kcache.SharedIndexInformer {
+	synced := map[string]kcache.SharedIndexInformer{
+		"namespaces":         namespaceInformer(clusterFactory, filter),
+		"nodes":              clusterFactory.Core().V1().Nodes().Informer(),
+		"persistent-volumes": clusterFactory.Core().V1().PersistentVolumes().Informer(),
+		"storage-classes":    clusterFactory.Storage().V1().StorageClasses().Informer(),
+	}
+
+	for namespace, factory := range factories {
+		prefix := ""
+		if namespace != v1.NamespaceAll {
+			prefix = namespace + "/"
+		}
+
+		for name, informer := range map[string]kcache.SharedIndexInformer{
+			"daemon-sets":   factory.Apps().V1().DaemonSets().Informer(),
+			"deployments":   factory.Apps().V1().Deployments().Informer(),
+			"replica-sets":  factory.Apps().V1().ReplicaSets().Informer(),
+			"stateful-sets": factory.Apps().V1().StatefulSets().Informer(),
+			"hpas":          factory.Autoscaling().V2().HorizontalPodAutoscalers().Informer(),
+			"cron-jobs":     factory.Batch().V1().CronJobs().Informer(),
+			"jobs":          factory.Batch().V1().Jobs().Informer(),
+			"config-maps":   factory.Core().V1().ConfigMaps().Informer(),
+			"pvcs":          factory.Core().V1().PersistentVolumeClaims().Informer(),
+			"pods":          factory.Core().V1().Pods().Informer(),
+			"secrets":       factory.Core().V1().Secrets().Informer(),
+			"services":      factory.Core().V1().Services().Informer(),
+			"endpoints":     factory.Discovery().V1().EndpointSlices().Informer(),
+			"events":        factory.Events().V1().Events().Informer(),
+			"ingresses":     factory.Networking().V1().Ingresses().Informer(),
+			"pdbs":          factory.Policy().V1().PodDisruptionBudgets().Informer(),
+		} {
+			synced[prefix+name] = informer
+		}
+	}
+
+	return synced
+}
+
+// warmUpInformers starts the informers of all synchronized resources and waits until their caches are synced.
+func warmUpInformers(
+	ctx context.Context, clusterFactory informers.SharedInformerFactory,
+	factories map[string]informers.SharedInformerFactory, filter *cluster.FilterConfig,
+) {
+	syncedInformers(clusterFactory, factories, filter)
+
+	clusterFactory.Start(ctx.Done())
+	for _, factory := range factories {
+		factory.Start(ctx.Done())
+	}
+
+	clusterFactory.WaitForCacheSync(ctx.Done())
+	for _, factory := range factories {
+		factory.WaitForCacheSync(ctx.Done())
 	}
 }
 
 // dbHasSchema queries via db whether the database dbName has a table named "kubernetes_schema".
+// For PostgreSQL, only the current schema of the database dbName is considered.
 func dbHasSchema(db *kdatabase.Database, dbName string) (bool, error) {
-	rows, err := db.Query(
-		db.Rebind("SELECT 1 FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA=? AND TABLE_NAME='kubernetes_schema'"),
-		dbName,
-	)
+	var query string
+	switch db.DriverName() {
+	case kdatabase.MySQL:
+		query = "SELECT 1 FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA=? AND TABLE_NAME='kubernetes_schema'"
+	case kdatabase.PostgreSQL:
+		query = "SELECT 1 FROM information_schema.tables" +
+			" WHERE table_catalog=? AND table_schema=current_schema() AND table_name='kubernetes_schema'"
+	}
+
+	rows, err := db.Query(db.Rebind(query), dbName)
 	if err != nil {
 		return false, err
 	}
@@ -601,7 +1260,10 @@ func dbHasSchema(db *kdatabase.Database, dbName string) (bool, error) {
 	return rows.Next(), rows.Err()
 }
 
-func SyncServicePods(ctx context.Context, db *kdatabase.Database, serviceList v2.ServiceInformer, podList v2.PodInformer) error {
+func SyncServicePods(
+	ctx context.Context, db *kdatabase.Database, multiplexers cachev1.EventsMultiplexers,
+	serviceList v2.ServiceInformer, podList v2.PodInformer,
+) error {
 	servicePods := make(chan any)
 
 	g, ctx := errgroup.WithContext(ctx)
@@ -610,7 +1272,7 @@ func SyncServicePods(ctx context.Context, db *kdatabase.Database, serviceList v2
 	})
 
 	g.Go(func() error {
-		ch := cachev1.Multiplexers().Pods().UpsertEvents().Out()
+		ch := multiplexers.Pods().UpsertEvents().Out()
 		for {
 			select {
 			case pod, more := <-ch:
@@ -618,7 +1280,7 @@ func SyncServicePods(ctx context.Context, db *kdatabase.Database, serviceList v2
 					return nil
 				}
 
-				services, err := serviceList.Lister().List(labels.NewSelector())
+				services, err := serviceList.Lister().Services(pod.(*schemav1.Pod).Namespace).List(labels.Everything())
 				if err != nil {
 					return err
 				}
@@ -657,7 +1319,7 @@ func SyncServicePods(ctx context.Context, db *kdatabase.Database, serviceList v2
 	})
 
 	g.Go(func() error {
-		ch := cachev1.Multiplexers().Services().UpsertEvents().Out()
+		ch := multiplexers.Services().UpsertEvents().Out()
 		for {
 			select {
 			case service, more := <-ch:
@@ -702,3 +1364,136 @@ func SyncServicePods(ctx context.Context, db *kdatabase.Database, serviceList v2
 
 	return g.Wait()
 }
+
+// SyncPdbPods links PDBs to the pods they select in the given namespace, which may be v1.NamespaceAll.
+// The links of a pod or PDB are replaced as a whole on every update, so that links that no longer apply,
+// e.g. after label or selector changes, are removed. The links of deleted PDBs are deleted along with them.
+func SyncPdbPods(
+	ctx context.Context, db *kdatabase.Database, multiplexers cachev1.EventsMultiplexers, namespace string,
+	pdbList kpolicyinformersv1.PodDisruptionBudgetInformer, podList v2.PodInformer,
+) error {
+	upsertPods := multiplexers.Pods().UpsertEvents().Out()
+	deletePods := multiplexers.Pods().DeleteEvents().Out()
+	upsertPdbs := multiplexers.Pdbs().UpsertEvents().Out()
+
+	for {
+		select {
+		case pod, more := <-upsertPods:
+			if !more {
+				return nil
+			}
+
+			if namespace != v1.NamespaceAll && pod.(*schemav1.Pod).Namespace != namespace {
+				continue
+			}
+
+			pdbs, err := pdbList.Lister().PodDisruptionBudgets(pod.(*schemav1.Pod).Namespace).List(labels.Everything())
+			if err != nil {
+				return err
+			}
+
+			podLabels := make(labels.Set)
+			for _, label := range pod.(*schemav1.Pod).Labels {
+				podLabels[label.Name] = label.Value
+			}
+
+			var pdbPods []schemav1.PdbPod
+			for _, pdb := range pdbs {
+				selector, err := v1.LabelSelectorAsSelector(pdb.Spec.Selector)
+				if err != nil {
+					return err
+				}
+
+				if selector.Matches(podLabels) {
+					pdbPods = append(pdbPods, schemav1.PdbPod{
+						PdbUuid: schemav1.EnsureUUID(pdb.UID),
+						PodUuid: pod.(*schemav1.Pod).Uuid,
+					})
+				}
+			}
+
+			if err := replacePdbPods(ctx, db, "pod_uuid", pod.(*schemav1.Pod).Uuid, pdbPods); err != nil {
+				return err
+			}
+		case podUuid, more := <-deletePods:
+			if !more {
+				return nil
+			}
+
+			if err := replacePdbPods(ctx, db, "pod_uuid", podUuid.(types.UUID), nil); err != nil {
+				return err
+			}
+		case pdb, more := <-upsertPdbs:
+			if !more {
+				return nil
+			}
+
+			if namespace != v1.NamespaceAll && pdb.(*schemav1.Pdb).Namespace != namespace {
+				continue
+			}
+
+			// The selector is not part of the database model, so it is taken from the informer's cache.
+			// If the PDB has been deleted in the meantime, its links are deleted along with it.
+			kpdb, err := pdbList.Lister().PodDisruptionBudgets(pdb.(*schemav1.Pdb).Namespace).Get(pdb.(*schemav1.Pdb).Name)
+			if err != nil {
+				if kerrors.IsNotFound(err) {
+					continue
+				}
+
+				return err
+			}
+
+			selector, err := v1.LabelSelectorAsSelector(kpdb.Spec.Selector)
+			if err != nil {
+				return err
+			}
+
+			pods, err := podList.Lister().Pods(kpdb.Namespace).List(selector)
+			if err != nil {
+				return err
+			}
+
+			var pdbPods []schemav1.PdbPod
+			for _, pod := range pods {
+				pdbPods = append(pdbPods, schemav1.PdbPod{
+					PdbUuid: pdb.(*schemav1.Pdb).Uuid,
+					PodUuid: schemav1.EnsureUUID(pod.UID),
+				})
+			}
+
+			if err := replacePdbPods(ctx, db, "pdb_uuid", pdb.(*schemav1.Pdb).Uuid, pdbPods); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// replacePdbPods replaces the pdb_pod rows whose column equals the given UUID with the given ones in a transaction.
+func replacePdbPods(
+	ctx context.Context, db *kdatabase.Database, column string, uuid types.UUID, pdbPods []schemav1.PdbPod,
+) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "cannot start transaction")
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	table := kdatabase.TableName(&schemav1.PdbPod{})
+	stmt := db.Rebind(fmt.Sprintf(
+		"DELETE FROM %s WHERE %s = ?", db.QuoteIdentifier(table), db.QuoteIdentifier(column)))
+	if _, err := tx.ExecContext(ctx, stmt, uuid); err != nil {
+		return errors.Wrapf(err, "cannot delete %s rows", table)
+	}
+
+	upsert, _ := db.BuildUpsertStmt(&schemav1.PdbPod{})
+	for _, pdbPod := range pdbPods {
+		if _, err := tx.NamedExecContext(ctx, upsert, pdbPod); err != nil {
+			return errors.Wrapf(err, "cannot insert %s row", table)
+		}
+	}
+
+	return errors.Wrap(tx.Commit(), "cannot commit transaction")
+}
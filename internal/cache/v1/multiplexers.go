@@ -13,18 +13,44 @@ type EventsMultiplexer interface {
 }
 
 type EventsMultiplexers interface {
+	CronJobs() EventsMultiplexer
 	DaemonSets() EventsMultiplexer
 	Deployments() EventsMultiplexer
+	Hpas() EventsMultiplexer
+	Ingresses() EventsMultiplexer
+	Jobs() EventsMultiplexer
+	Namespaces() EventsMultiplexer
 	Nodes() EventsMultiplexer
+	Pdbs() EventsMultiplexer
+	PersistentVolumes() EventsMultiplexer
 	Pods() EventsMultiplexer
+	Pvcs() EventsMultiplexer
 	ReplicaSets() EventsMultiplexer
 	Services() EventsMultiplexer
 	StatefulSets() EventsMultiplexer
 	Run(context.Context) error
 }
 
-func Multiplexers() EventsMultiplexers {
-	return m
+// NewMultiplexers returns a new set of EventsMultiplexers.
+// Each synchronized cluster uses its own set so that events of different clusters are not mixed up.
+func NewMultiplexers() EventsMultiplexers {
+	return multiplexers{
+		cronJobs:          newEvents(),
+		daemonSets:        newEvents(),
+		deployments:       newEvents(),
+		hpas:              newEvents(),
+		ingresses:         newEvents(),
+		jobs:              newEvents(),
+		namespaces:        newEvents(),
+		nodes:             newEvents(),
+		pdbs:              newEvents(),
+		persistentVolumes: newEvents(),
+		pods:              newEvents(),
+		pvcs:              newEvents(),
+		replicaSets:       newEvents(),
+		services:          newEvents(),
+		statefulSets:      newEvents(),
+	}
 }
 
 type events struct {
@@ -32,6 +58,13 @@ type events struct {
 	deleteEvents internal.ChannelMultiplexer[any]
 }
 
+func newEvents() events {
+	return events{
+		upsertEvents: internal.NewChannelMux[any](),
+		deleteEvents: internal.NewChannelMux[any](),
+	}
+}
+
 func (e events) UpsertEvents() internal.ChannelMultiplexer[any] {
 	return e.upsertEvents
 }
@@ -55,13 +88,25 @@ func (e events) Run(ctx context.Context) error {
 }
 
 type multiplexers struct {
-	daemonSets   events
-	deployments  events
-	nodes        events
-	pods         events
-	replicaSets  events
-	services     events
-	statefulSets events
+	cronJobs          events
+	daemonSets        events
+	deployments       events
+	hpas              events
+	ingresses         events
+	jobs              events
+	namespaces        events
+	nodes             events
+	pdbs              events
+	persistentVolumes events
+	pods              events
+	pvcs              events
+	replicaSets       events
+	services          events
+	statefulSets      events
+}
+
+func (m multiplexers) CronJobs() EventsMultiplexer {
+	return m.cronJobs
 }
 
 func (m multiplexers) DaemonSets() EventsMultiplexer {
@@ -72,14 +117,42 @@ func (m multiplexers) Deployments() EventsMultiplexer {
 	return m.deployments
 }
 
+func (m multiplexers) Hpas() EventsMultiplexer {
+	return m.hpas
+}
+
+func (m multiplexers) Ingresses() EventsMultiplexer {
+	return m.ingresses
+}
+
+func (m multiplexers) Jobs() EventsMultiplexer {
+	return m.jobs
+}
+
+func (m multiplexers) Namespaces() EventsMultiplexer {
+	return m.namespaces
+}
+
 func (m multiplexers) Nodes() EventsMultiplexer {
 	return m.nodes
 }
 
+func (m multiplexers) Pdbs() EventsMultiplexer {
+	return m.pdbs
+}
+
+func (m multiplexers) PersistentVolumes() EventsMultiplexer {
+	return m.persistentVolumes
+}
+
 func (m multiplexers) Pods() EventsMultiplexer {
 	return m.pods
 }
 
+func (m multiplexers) Pvcs() EventsMultiplexer {
+	return m.pvcs
+}
+
 func (m multiplexers) ReplicaSets() EventsMultiplexer {
 	return m.replicaSets
 }
@@ -95,6 +168,10 @@ func (m multiplexers) StatefulSets() EventsMultiplexer {
 func (m multiplexers) Run(ctx context.Context) error {
 	g, ctx := errgroup.WithContext(ctx)
 
+	g.Go(func() error {
+		return m.cronJobs.Run(ctx)
+	})
+
 	g.Go(func() error {
 		return m.daemonSets.Run(ctx)
 	})
@@ -103,14 +180,42 @@ func (m multiplexers) Run(ctx context.Context) error {
 		return m.deployments.Run(ctx)
 	})
 
+	g.Go(func() error {
+		return m.hpas.Run(ctx)
+	})
+
+	g.Go(func() error {
+		return m.ingresses.Run(ctx)
+	})
+
+	g.Go(func() error {
+		return m.jobs.Run(ctx)
+	})
+
+	g.Go(func() error {
+		return m.namespaces.Run(ctx)
+	})
+
 	g.Go(func() error {
 		return m.nodes.Run(ctx)
 	})
 
+	g.Go(func() error {
+		return m.pdbs.Run(ctx)
+	})
+
+	g.Go(func() error {
+		return m.persistentVolumes.Run(ctx)
+	})
+
 	g.Go(func() error {
 		return m.pods.Run(ctx)
 	})
 
+	g.Go(func() error {
+		return m.pvcs.Run(ctx)
+	})
+
 	g.Go(func() error {
 		return m.replicaSets.Run(ctx)
 	})
@@ -125,38 +230,3 @@ func (m multiplexers) Run(ctx context.Context) error {
 
 	return g.Wait()
 }
-
-var m multiplexers
-
-func init() {
-	m = multiplexers{
-		daemonSets: events{
-			upsertEvents: internal.NewChannelMux[any](),
-			deleteEvents: internal.NewChannelMux[any](),
-		},
-		deployments: events{
-			upsertEvents: internal.NewChannelMux[any](),
-			deleteEvents: internal.NewChannelMux[any](),
-		},
-		nodes: events{
-			upsertEvents: internal.NewChannelMux[any](),
-			deleteEvents: internal.NewChannelMux[any](),
-		},
-		pods: events{
-			upsertEvents: internal.NewChannelMux[any](),
-			deleteEvents: internal.NewChannelMux[any](),
-		},
-		replicaSets: events{
-			upsertEvents: internal.NewChannelMux[any](),
-			deleteEvents: internal.NewChannelMux[any](),
-		},
-		services: events{
-			upsertEvents: internal.NewChannelMux[any](),
-			deleteEvents: internal.NewChannelMux[any](),
-		},
-		statefulSets: events{
-			upsertEvents: internal.NewChannelMux[any](),
-			deleteEvents: internal.NewChannelMux[any](),
-		},
-	}
-}
@@ -41,10 +41,10 @@ func SyncNotificationsConfig(ctx context.Context, db *database.DB, config *notif
 
 			if _, err := tx.ExecContext(
 				ctx,
-				fmt.Sprintf(
+				tx.Rebind(fmt.Sprintf(
 					`DELETE FROM "%s" WHERE "cluster_uuid" = ? AND "key" LIKE ? AND "locked" = ?`,
 					database.TableName(&schemav1.Config{}),
-				),
+				)),
 				clusterUuid,
 				`notifications.%`,
 				_true,
@@ -66,10 +66,10 @@ func SyncNotificationsConfig(ctx context.Context, db *database.DB, config *notif
 		err := db.ExecTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
 			if _, err := tx.ExecContext(
 				ctx,
-				fmt.Sprintf(
+				tx.Rebind(fmt.Sprintf(
 					`DELETE FROM "%s" WHERE "cluster_uuid" = ? AND "key" LIKE ? AND "locked" = ?`,
 					database.TableName(&schemav1.Config{}),
-				),
+				)),
 				clusterUuid,
 				`notifications.%`,
 				_true,
@@ -109,3 +109,28 @@ func SyncNotificationsConfig(ctx context.Context, db *database.DB, config *notif
 
 	return nil
 }
+
+// RestoreNotifiedStates remembers the Icinga states of the objects of the given cluster stored in the tables of the
+// given subjects as already notified, so that only state transitions since the last run are notified after a restart.
+func RestoreNotifiedStates(
+	ctx context.Context, db *database.DB, client *notifications.Client, clusterUuid types.UUID, subjects ...any,
+) error {
+	for _, subject := range subjects {
+		var rows []struct {
+			Uuid        types.UUID
+			IcingaState schemav1.IcingaState
+		}
+		if err := db.SelectContext(ctx, &rows, db.Rebind(fmt.Sprintf(
+			`SELECT "uuid", "icinga_state" FROM "%s" WHERE "cluster_uuid" = ?`,
+			database.TableName(subject),
+		)), clusterUuid); err != nil {
+			return errors.Wrapf(err, "cannot select Icinga states from %s", database.TableName(subject))
+		}
+
+		for _, row := range rows {
+			client.Remember(row.Uuid, row.IcingaState.ToSeverity())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"github.com/icinga/icinga-go-library/database"
+	"github.com/icinga/icinga-go-library/types"
+	kdatabase "github.com/icinga/icinga-kubernetes/pkg/database"
+	schemav1 "github.com/icinga/icinga-kubernetes/pkg/schema/v1"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"strconv"
+)
+
+// SyncRetentionConfig persists the effective retention configuration in the config table, so that it can be
+// displayed in the web UI. The rows are locked, as the retention can only be configured via the config file.
+func SyncRetentionConfig(ctx context.Context, db *database.DB, config *kdatabase.RetentionConfig, clusterUuid types.UUID) error {
+	_true := types.Bool{Bool: true, Valid: true}
+
+	disabled := "n"
+	if config.Disabled {
+		disabled = "y"
+	}
+
+	toDb := []schemav1.Config{
+		{ClusterUuid: clusterUuid, Key: schemav1.ConfigKeyRetentionDisabled, Value: disabled, Locked: _true},
+	}
+	for _, category := range kdatabase.RetentionCategories {
+		toDb = append(toDb, schemav1.Config{
+			ClusterUuid: clusterUuid,
+			Key:         schemav1.ConfigKeyRetention(category),
+			Value:       strconv.FormatUint(uint64(config.DaysFor(category)), 10),
+			Locked:      _true,
+		})
+	}
+
+	err := db.ExecTx(ctx, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(
+			ctx,
+			tx.Rebind(fmt.Sprintf(
+				`DELETE FROM "%s" WHERE "cluster_uuid" = ? AND "key" LIKE ?`,
+				database.TableName(&schemav1.Config{}),
+			)),
+			clusterUuid,
+			`retention.%`,
+		); err != nil {
+			return errors.Wrap(err, "cannot delete retention config")
+		}
+
+		stmt, _ := db.BuildInsertStmt(schemav1.Config{})
+		if _, err := tx.NamedExecContext(ctx, stmt, toDb); err != nil {
+			return errors.Wrap(err, "cannot insert retention config")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "cannot upsert retention config")
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package cluster
+
+import (
+	"github.com/icinga/icinga-kubernetes/pkg/metrics"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/rest"
+	kclientcmd "k8s.io/client-go/tools/clientcmd"
+)
+
+// Config defines the configuration of a single Kubernetes cluster to synchronize.
+type Config struct {
+	// Name is the name of the cluster, which is displayed in the UI.
+	Name string `yaml:"name"`
+	// Kubeconfig is the path to the kubeconfig file to use. If empty,
+	// the default loading rules are used, i.e. KUBECONFIG, ~/.kube/config or the in-cluster config.
+	Kubeconfig string `yaml:"kubeconfig"`
+	// Context is the kubeconfig context to use. If empty, the current context is used.
+	Context string `yaml:"context"`
+	// Prometheus configures the Prometheus of this cluster, which is auto-detected if not configured.
+	// Metric categories, queries and thresholds are inherited from the top-level Prometheus configuration.
+	Prometheus metrics.PrometheusConfig `yaml:"prometheus"`
+	// Filter overrides the top-level filter configuration for this cluster.
+	Filter FilterConfig `yaml:"filter"`
+}
+
+// ClientConfig returns the Kubernetes client configuration for the cluster.
+func (c *Config) ClientConfig() (*rest.Config, error) {
+	loadingRules := kclientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.DefaultClientConfig = &kclientcmd.DefaultClientConfig
+	loadingRules.ExplicitPath = c.Kubeconfig
+
+	overrides := &kclientcmd.ConfigOverrides{CurrentContext: c.Context}
+
+	kconfig, err := kclientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot configure Kubernetes client for cluster %q", c.Name)
+	}
+
+	return kconfig, nil
+}
+
+// Validate checks constraints in the supplied cluster configuration and returns an error if they are violated.
+func (c *Config) Validate() error {
+	if c.Name == "" {
+		return errors.New("'name' is required for each cluster")
+	}
+
+	if err := c.Prometheus.Validate(); err != nil {
+		return errors.Wrapf(err, "invalid Prometheus configuration for cluster %q", c.Name)
+	}
+
+	if err := c.Filter.Validate(); err != nil {
+		return errors.Wrapf(err, "invalid filter configuration for cluster %q", c.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package cluster
+
+import (
+	"github.com/pkg/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"slices"
+)
+
+// FilterConfig defines which objects of a Kubernetes cluster to synchronize.
+type FilterConfig struct {
+	// Namespaces restricts synchronization of namespaced resources to the given namespaces.
+	// If empty, all namespaces are synchronized. Since each of these namespaces is watched separately,
+	// read access to the namespaced resources of these namespaces is sufficient.
+	Namespaces []string `yaml:"namespaces"`
+	// ExcludeNamespaces excludes the given namespaces and their resources from synchronization.
+	ExcludeNamespaces []string `yaml:"exclude_namespaces"`
+	// LabelSelector restricts synchronization to objects whose labels match the selector,
+	// e.g. "environment in (production, staging),tier!=frontend".
+	LabelSelector string `yaml:"label_selector"`
+}
+
+// IsEmpty returns whether the filter does not restrict synchronization at all.
+func (f *FilterConfig) IsEmpty() bool {
+	return len(f.Namespaces) == 0 && len(f.ExcludeNamespaces) == 0 && f.LabelSelector == ""
+}
+
+// Scopes returns the namespaces to watch separately. If no namespaces are included,
+// this is only kmetav1.NamespaceAll, i.e. all namespaces are watched at once.
+func (f *FilterConfig) Scopes() []string {
+	if len(f.Namespaces) == 0 {
+		return []string{kmetav1.NamespaceAll}
+	}
+
+	return f.Namespaces
+}
+
+// WarmupNamespaces returns the namespaces whose database rows belong to the given scope,
+// which is one of Scopes(). If include is empty, all rows except those of the namespaces in exclude belong to it.
+// Rows of namespaces that are no longer synchronized are assigned to the first scope,
+// so that they are deleted after its informer has listed all objects of its namespace.
+func (f *FilterConfig) WarmupNamespaces(scope string) (include, exclude []string) {
+	if len(f.Namespaces) == 0 || scope != f.Namespaces[0] {
+		if scope != kmetav1.NamespaceAll {
+			include = []string{scope}
+		}
+
+		return
+	}
+
+	return nil, f.Namespaces[1:]
+}
+
+// TweakListOptions applies the label selector and, for namespaced resources,
+// the excluded namespaces to the given options.
+func (f *FilterConfig) TweakListOptions(options *kmetav1.ListOptions, namespaced bool) {
+	options.LabelSelector = f.LabelSelector
+
+	if namespaced && len(f.ExcludeNamespaces) > 0 {
+		selectors := make([]fields.Selector, 0, len(f.ExcludeNamespaces))
+		for _, namespace := range f.ExcludeNamespaces {
+			selectors = append(selectors, fields.OneTermNotEqualSelector("metadata.namespace", namespace))
+		}
+
+		options.FieldSelector = fields.AndSelectors(selectors...).String()
+	}
+}
+
+// TweakNamespaceListOptions applies the label selector and the excluded namespaces to
+// the given options for listing namespaces.
+func (f *FilterConfig) TweakNamespaceListOptions(options *kmetav1.ListOptions) {
+	options.LabelSelector = f.LabelSelector
+
+	if len(f.ExcludeNamespaces) > 0 {
+		selectors := make([]fields.Selector, 0, len(f.ExcludeNamespaces))
+		for _, namespace := range f.ExcludeNamespaces {
+			selectors = append(selectors, fields.OneTermNotEqualSelector("metadata.name", namespace))
+		}
+
+		options.FieldSelector = fields.AndSelectors(selectors...).String()
+	}
+}
+
+// Validate checks constraints in the supplied filter configuration and returns an error if they are violated.
+func (f *FilterConfig) Validate() error {
+	for i, namespace := range f.Namespaces {
+		if namespace == "" {
+			return errors.New("'namespaces' must not contain empty namespace names")
+		}
+
+		if slices.Contains(f.Namespaces[:i], namespace) {
+			return errors.Errorf("namespace %q is included more than once", namespace)
+		}
+
+		if slices.Contains(f.ExcludeNamespaces, namespace) {
+			return errors.Errorf("namespace %q must not be included and excluded at the same time", namespace)
+		}
+	}
+
+	if _, err := labels.Parse(f.LabelSelector); err != nil {
+		return errors.Wrap(err, "invalid 'label_selector'")
+	}
+
+	return nil
+}
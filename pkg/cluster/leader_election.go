@@ -0,0 +1,135 @@
+package cluster
+
+import (
+	"context"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrLeadershipLost is the cause of the cancellation of the context returned by AwaitLeadership
+// once the lease has been lost.
+var ErrLeadershipLost = errors.New("leadership lost")
+
+// serviceAccountNamespace is the file that contains the namespace of the pod if running in a Kubernetes cluster.
+const serviceAccountNamespace = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// LeaderElectionConfig defines the configuration of the Lease-based leader election,
+// which allows running multiple replicas of which only the leader synchronizes.
+type LeaderElectionConfig struct {
+	// Enabled enables leader election.
+	Enabled bool `yaml:"enabled"`
+	// Namespace is the namespace of the Lease. If empty, the namespace of the pod is used,
+	// if running in a Kubernetes cluster, or "default" otherwise.
+	Namespace string `yaml:"namespace"`
+	// LeaseName is the name of the Lease.
+	LeaseName string `yaml:"lease_name" default:"icinga-kubernetes"`
+	// LeaseDuration is the duration that followers wait before they try to acquire a lease that has not been renewed.
+	LeaseDuration time.Duration `yaml:"lease_duration" default:"15s"`
+	// RenewDeadline is the duration that the leader retries to renew the lease before it gives up leadership.
+	RenewDeadline time.Duration `yaml:"renew_deadline" default:"10s"`
+	// RetryPeriod is the duration between attempts to acquire or renew the lease.
+	RetryPeriod time.Duration `yaml:"retry_period" default:"2s"`
+}
+
+// LeaseNamespace returns the namespace of the Lease.
+func (c *LeaderElectionConfig) LeaseNamespace() string {
+	if c.Namespace != "" {
+		return c.Namespace
+	}
+
+	if namespace, err := os.ReadFile(serviceAccountNamespace); err == nil {
+		if namespace := strings.TrimSpace(string(namespace)); namespace != "" {
+			return namespace
+		}
+	}
+
+	return kmetav1.NamespaceDefault
+}
+
+// Validate checks constraints in the supplied leader election configuration and
+// returns an error if they are violated.
+func (c *LeaderElectionConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.LeaseName == "" {
+		return errors.New("'lease_name' is required for leader election")
+	}
+
+	if c.RetryPeriod <= 0 {
+		return errors.New("'retry_period' must be greater than zero")
+	}
+
+	if c.RenewDeadline <= c.RetryPeriod {
+		return errors.New("'renew_deadline' must be greater than 'retry_period'")
+	}
+
+	if c.LeaseDuration <= c.RenewDeadline {
+		return errors.New("'lease_duration' must be greater than 'renew_deadline'")
+	}
+
+	return nil
+}
+
+// AwaitLeadership blocks until this replica, identified by identity, has acquired the lease in the cluster
+// accessible via clientset. It returns a context derived from ctx that is canceled with ErrLeadershipLost
+// as cause once the lease cannot be renewed. On cancellation of ctx, the lease is released.
+func AwaitLeadership(
+	ctx context.Context, clientset kubernetes.Interface, config *LeaderElectionConfig, identity string, log logr.Logger,
+) (context.Context, error) {
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: kmetav1.ObjectMeta{
+			Namespace: config.LeaseNamespace(),
+			Name:      config.LeaseName,
+		},
+		Client:     clientset.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+	}
+
+	leaderCtx, cancel := context.WithCancelCause(ctx)
+	started := make(chan struct{})
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   config.LeaseDuration,
+		RenewDeadline:   config.RenewDeadline,
+		RetryPeriod:     config.RetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            config.LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {
+				close(started)
+			},
+			OnStoppedLeading: func() {
+				cancel(ErrLeadershipLost)
+			},
+			OnNewLeader: func(leader string) {
+				log.Info("Leader elected", "leader", leader, "self", leader == identity)
+			},
+		},
+	})
+	if err != nil {
+		cancel(err)
+
+		return nil, errors.Wrap(err, "cannot create leader elector")
+	}
+
+	go elector.Run(leaderCtx)
+
+	log.Info("Waiting for leadership", "lease", lock.Describe())
+
+	select {
+	case <-started:
+		return leaderCtx, nil
+	case <-leaderCtx.Done():
+		return nil, context.Cause(leaderCtx)
+	}
+}
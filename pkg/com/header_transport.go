@@ -0,0 +1,22 @@
+package com
+
+import (
+	"net/http"
+)
+
+// HeaderTransport is a http.RoundTripper that sets the given headers on all requests.
+type HeaderTransport struct {
+	http.RoundTripper
+	Headers map[string]string
+}
+
+// RoundTrip executes a single HTTP transaction with the headers set.
+func (t *HeaderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the given request.
+	req = req.Clone(req.Context())
+	for name, value := range t.Headers {
+		req.Header.Set(name, value)
+	}
+
+	return t.RoundTripper.RoundTrip(req)
+}
@@ -3,16 +3,33 @@ package daemon
 import (
 	"github.com/icinga/icinga-go-library/database"
 	"github.com/icinga/icinga-go-library/logging"
+	"github.com/icinga/icinga-kubernetes/pkg/cluster"
+	kdatabase "github.com/icinga/icinga-kubernetes/pkg/database"
 	"github.com/icinga/icinga-kubernetes/pkg/metrics"
 	"github.com/icinga/icinga-kubernetes/pkg/notifications"
+	"github.com/icinga/icinga-kubernetes/pkg/rules"
+	"github.com/icinga/icinga-kubernetes/pkg/telemetry"
+	"github.com/pkg/errors"
 )
 
 // Config defines Icinga Kubernetes config.
 type Config struct {
-	Database      database.Config          `yaml:"database"`
-	Logging       logging.Config           `yaml:"logging"`
-	Notifications notifications.Config     `yaml:"notifications"`
-	Prometheus    metrics.PrometheusConfig `yaml:"prometheus"`
+	Database      database.Config           `yaml:"database"`
+	Logging       logging.Config            `yaml:"logging"`
+	Notifications notifications.Config      `yaml:"notifications"`
+	Prometheus    metrics.PrometheusConfig  `yaml:"prometheus"`
+	Retention     kdatabase.RetentionConfig `yaml:"retention"`
+	// StateRules override or augment the Icinga states of Kubernetes objects.
+	StateRules rules.Config `yaml:"state_rules"`
+	// Telemetry configures the HTTP server that exposes health checks and metrics.
+	Telemetry telemetry.Config `yaml:"telemetry"`
+	// LeaderElection allows running multiple replicas of which only the leader synchronizes.
+	LeaderElection cluster.LeaderElectionConfig `yaml:"leader_election"`
+	// Filter restricts which namespaces and objects are synchronized.
+	Filter cluster.FilterConfig `yaml:"filter"`
+	// Clusters configures the Kubernetes clusters to synchronize. If empty,
+	// a single cluster is synchronized as configured via command line flags.
+	Clusters []cluster.Config `yaml:"clusters"`
 }
 
 // Validate checks constraints in the supplied configuration and returns an error if they are violated.
@@ -29,5 +46,40 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if err := c.Retention.Validate(); err != nil {
+		return errors.Wrap(err, "invalid retention configuration")
+	}
+
+	if err := c.StateRules.Validate(); err != nil {
+		return errors.Wrap(err, "invalid state rules configuration")
+	}
+
+	if err := c.LeaderElection.Validate(); err != nil {
+		return errors.Wrap(err, "invalid leader election configuration")
+	}
+
+	if err := c.Filter.Validate(); err != nil {
+		return errors.Wrap(err, "invalid filter configuration")
+	}
+
+	if len(c.Clusters) > 0 && c.Prometheus.ConnectionConfigured() {
+		return errors.New(
+			"the top-level Prometheus connection cannot be used with multiple clusters, " +
+				"configure it per cluster or let it be auto-detected")
+	}
+
+	names := make(map[string]struct{}, len(c.Clusters))
+	for i := range c.Clusters {
+		if err := c.Clusters[i].Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := names[c.Clusters[i].Name]; ok {
+			return errors.Errorf("cluster name %q must be unique", c.Clusters[i].Name)
+		}
+
+		names[c.Clusters[i].Name] = struct{}{}
+	}
+
 	return c.Notifications.Validate()
 }
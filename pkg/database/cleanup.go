@@ -8,6 +8,7 @@ import (
 	"github.com/icinga/icinga-go-library/periodic"
 	"github.com/icinga/icinga-go-library/retry"
 	"github.com/icinga/icinga-go-library/types"
+	"strings"
 	"time"
 )
 
@@ -25,10 +26,14 @@ func (stmt *CleanupStmt) Build(driverName string, limit uint64) string {
 		return fmt.Sprintf(`DELETE FROM %[1]s WHERE %[2]s < :time
 ORDER BY %[2]s LIMIT %[3]d`, stmt.Table, stmt.Column, limit)
 	case PostgreSQL, "postgres":
+		// A composite primary key such as (a, b) must be selected as plain column list,
+		// as PostgreSQL would otherwise yield a single column of a row type.
+		columns := strings.Trim(stmt.PK, "()")
+
 		return fmt.Sprintf(`WITH rows AS (
-SELECT %[1]s FROM %[2]s WHERE %[3]s < :time ORDER BY %[3]s LIMIT %[4]d
+SELECT %[5]s FROM %[2]s WHERE %[3]s < :time ORDER BY %[3]s LIMIT %[4]d
 )
-DELETE FROM %[2]s WHERE %[1]s IN (SELECT %[1]s FROM rows)`, stmt.PK, stmt.Table, stmt.Column, limit)
+DELETE FROM %[2]s WHERE %[1]s IN (SELECT %[5]s FROM rows)`, stmt.PK, stmt.Table, stmt.Column, limit, columns)
 	default:
 		panic(fmt.Sprintf("invalid database type %s", driverName))
 	}
@@ -105,12 +110,13 @@ type cleanupWhere struct {
 	Time types.UnixMilli
 }
 
-func (db *Database) PeriodicCleanup(ctx context.Context, stmt CleanupStmt) error {
+// PeriodicCleanup deletes rows with the specified statement that are older than the given number of days every hour.
+func (db *Database) PeriodicCleanup(ctx context.Context, stmt CleanupStmt, days uint16) error {
 	errs := make(chan error, 1)
 	defer close(errs)
 
 	periodic.Start(ctx, time.Hour, func(tick periodic.Tick) {
-		olderThan := tick.Time.AddDate(0, 0, -1)
+		olderThan := tick.Time.AddDate(0, 0, -int(days))
 
 		_, err := db.CleanupOlderThan(
 			ctx, stmt, 5000, olderThan,
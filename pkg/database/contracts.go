@@ -9,3 +9,10 @@ type TableNamer interface {
 type Upserter interface {
 	Upsert() interface{}
 }
+
+// PgsqlOnConflictConstrainter implements the PgsqlOnConflictConstraint method,
+// which returns the primary or unique key constraint name of the PostgreSQL table.
+type PgsqlOnConflictConstrainter interface {
+	// PgsqlOnConflictConstraint returns the primary or unique key constraint name of the PostgreSQL table.
+	PgsqlOnConflictConstraint() string
+}
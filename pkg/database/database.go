@@ -11,6 +11,7 @@ import (
 	"github.com/icinga/icinga-go-library/periodic"
 	"github.com/icinga/icinga-go-library/retry"
 	"github.com/icinga/icinga-go-library/strcase"
+	"github.com/icinga/icinga-kubernetes/pkg/telemetry"
 	"github.com/jmoiron/sqlx"
 	"github.com/jmoiron/sqlx/reflectx"
 	"github.com/pkg/errors"
@@ -20,6 +21,7 @@ import (
 	"net"
 	"net/url"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -146,7 +148,7 @@ func (db *Database) BuildDeleteStmt(from interface{}) string {
 	return fmt.Sprintf(
 		`DELETE FROM %s WHERE %s IN (?)`,
 		db.QuoteIdentifier(TableName(from)),
-		column,
+		db.QuoteIdentifier(column),
 	)
 }
 
@@ -188,8 +190,15 @@ func (db *Database) BuildUpsertStmt(subject interface{}) (stmt string, placehold
 		clause = "ON DUPLICATE KEY UPDATE"
 		setFormat = fmt.Sprintf("%[1]s = VALUES(%[1]s)", quoted)
 	case PostgreSQL:
-		clause = fmt.Sprintf("ON CONFLICT ON CONSTRAINT pk_%s DO UPDATE SET", table)
-		setFormat = `"%[1]s" = EXCLUDED."%[1]s"`
+		var constraint string
+		if constrainter, ok := subject.(PgsqlOnConflictConstrainter); ok {
+			constraint = constrainter.PgsqlOnConflictConstraint()
+		} else {
+			constraint = "pk_" + table
+		}
+
+		clause = fmt.Sprintf("ON CONFLICT ON CONSTRAINT %s DO UPDATE SET", constraint)
+		setFormat = fmt.Sprintf("%[1]s = EXCLUDED.%[1]s", quoted)
 	}
 
 	set := make([]string, 0, len(updateColumns))
@@ -252,11 +261,16 @@ func (db *Database) BulkExec(
 							}
 
 							stmt = db.Rebind(stmt)
+							start := time.Now()
 							_, err = db.ExecContext(ctx, stmt, args...)
 							if err != nil {
 								return CantPerformQuery(err, query)
 							}
 
+							if f.batchDuration != nil {
+								f.batchDuration.Observe(time.Since(start).Seconds())
+							}
+
 							counter.Add(uint64(len(b)))
 
 							if f.onSuccess != nil {
@@ -333,11 +347,16 @@ func (db *Database) NamedBulkExec(
 						return retry.WithBackoff(
 							ctx,
 							func(ctx context.Context) error {
+								start := time.Now()
 								_, err := db.NamedExecContext(ctx, query, b)
 								if err != nil {
 									return CantPerformQuery(err, query)
 								}
 
+								if with.batchDuration != nil {
+									with.batchDuration.Observe(time.Since(start).Seconds())
+								}
+
 								counter.Add(uint64(len(b)))
 
 								if with.onSuccess != nil {
@@ -386,6 +405,8 @@ func (db *Database) DeleteStreamed(
 	ctx context.Context, from interface{}, ids <-chan interface{}, features ...Feature,
 ) error {
 	f := NewFeatures(features...)
+	deleteFeatures := append(
+		slices.Clip(features), withBatchDuration(telemetry.DatabaseBatchDuration.WithLabelValues(TableName(from), "delete")))
 
 	if relations, ok := from.(HasRelations); ok && f.cascading {
 		var g *errgroup.Group
@@ -472,7 +493,7 @@ func (db *Database) DeleteStreamed(
 				db.Options.MaxPlaceholdersPerStatement,
 				db.GetSemaphoreForTable(TableName(from)),
 				ids,
-				features...,
+				deleteFeatures...,
 			)
 		})
 
@@ -485,7 +506,7 @@ func (db *Database) DeleteStreamed(
 		db.Options.MaxPlaceholdersPerStatement,
 		db.GetSemaphoreForTable(TableName(from)),
 		ids,
-		features...,
+		deleteFeatures...,
 	)
 }
 
@@ -504,6 +525,8 @@ func (db *Database) UpsertStreamed(
 	sem := db.GetSemaphoreForTable(TableName(first))
 	stmt, placeholders := db.BuildUpsertStmt(first)
 	with := NewFeatures(features...)
+	upsertFeatures := append(
+		slices.Clip(features), withBatchDuration(telemetry.DatabaseBatchDuration.WithLabelValues(TableName(first), "upsert")))
 
 	if relations, ok := first.(HasRelations); ok && with.cascading {
 		var g *errgroup.Group
@@ -582,14 +605,14 @@ func (db *Database) UpsertStreamed(
 			defer runtime.HandleCrash()
 
 			return db.NamedBulkExec(
-				ctx, stmt, db.BatchSizeByPlaceholders(placeholders), sem, forward, com.NeverSplit[any], features...)
+				ctx, stmt, db.BatchSizeByPlaceholders(placeholders), sem, forward, com.NeverSplit[any], upsertFeatures...)
 		})
 
 		return g.Wait()
 	}
 
 	return db.NamedBulkExec(
-		ctx, stmt, db.BatchSizeByPlaceholders(placeholders), sem, forward, com.NeverSplit[any], features...)
+		ctx, stmt, db.BatchSizeByPlaceholders(placeholders), sem, forward, com.NeverSplit[any], upsertFeatures...)
 }
 
 // YieldAll executes the query with the supplied scope,
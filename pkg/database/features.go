@@ -2,14 +2,16 @@ package database
 
 import (
 	"github.com/icinga/icinga-go-library/database"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 type Feature func(*Features)
 
 type Features struct {
-	blocking  bool
-	cascading bool
-	onSuccess database.OnSuccess[any]
+	blocking      bool
+	cascading     bool
+	onSuccess     database.OnSuccess[any]
+	batchDuration prometheus.Observer
 }
 
 func NewFeatures(features ...Feature) *Features {
@@ -38,3 +40,10 @@ func WithOnSuccess(fn database.OnSuccess[any]) Feature {
 		f.onSuccess = fn
 	}
 }
+
+// withBatchDuration observes the duration of each successfully executed batch.
+func withBatchDuration(observer prometheus.Observer) Feature {
+	return func(f *Features) {
+		f.batchDuration = observer
+	}
+}
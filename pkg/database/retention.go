@@ -0,0 +1,71 @@
+package database
+
+import "github.com/pkg/errors"
+
+// Retention categories, each of which covers one or more tables that are cleaned up periodically.
+const (
+	RetentionEvents           = "events"
+	RetentionClusterMetrics   = "cluster_metrics"
+	RetentionNodeMetrics      = "node_metrics"
+	RetentionPodMetrics       = "pod_metrics"
+	RetentionContainerMetrics = "container_metrics"
+	RetentionRollouts         = "rollouts"
+)
+
+// RetentionCategories lists all valid retention categories.
+var RetentionCategories = []string{
+	RetentionEvents,
+	RetentionClusterMetrics,
+	RetentionNodeMetrics,
+	RetentionPodMetrics,
+	RetentionContainerMetrics,
+	RetentionRollouts,
+}
+
+// RetentionConfig defines for how many days rows of time-based tables are retained before they are cleaned up.
+type RetentionConfig struct {
+	// Disabled disables the periodic cleanup, i.e. rows are retained forever.
+	Disabled bool `yaml:"disabled"`
+	// Days is the number of days to retain rows of categories without their own option.
+	Days uint16 `yaml:"days" default:"1"`
+	// Options overrides Days for individual categories, e.g. events: 30.
+	Options map[string]uint16 `yaml:"options"`
+}
+
+// DaysFor returns the number of days to retain rows of the given category.
+func (r *RetentionConfig) DaysFor(category string) uint16 {
+	if days, ok := r.Options[category]; ok {
+		return days
+	}
+
+	return r.Days
+}
+
+// Validate checks constraints in the supplied retention configuration and returns an error if they are violated.
+func (r *RetentionConfig) Validate() error {
+	if r.Days < 1 {
+		return errors.New("retention 'days' must be at least 1")
+	}
+
+	for category, days := range r.Options {
+		if !isRetentionCategory(category) {
+			return errors.Errorf("invalid retention category %q, must be one of %v", category, RetentionCategories)
+		}
+
+		if days < 1 {
+			return errors.Errorf("retention days for %q must be at least 1", category)
+		}
+	}
+
+	return nil
+}
+
+func isRetentionCategory(category string) bool {
+	for _, c := range RetentionCategories {
+		if c == category {
+			return true
+		}
+	}
+
+	return false
+}
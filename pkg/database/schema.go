@@ -0,0 +1,196 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"github.com/icinga/icinga-go-library/types"
+	"github.com/pkg/errors"
+	"io/fs"
+	"k8s.io/apimachinery/pkg/util/version"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SchemaUpgrade is a single versioned upgrade script that migrates the database schema to Version.
+type SchemaUpgrade struct {
+	Version *version.Version
+	DDL     string
+}
+
+// SchemaUpgrades reads all upgrade scripts from the given file system, ordered by version.
+// Upgrade scripts are expected to be named after the schema version they upgrade to, e.g. 0.3.0.sql.
+func SchemaUpgrades(upgrades fs.FS) ([]SchemaUpgrade, error) {
+	files, err := fs.Glob(upgrades, "*.sql")
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot list schema upgrades")
+	}
+
+	schemaUpgrades := make([]SchemaUpgrade, 0, len(files))
+	for _, file := range files {
+		v, err := version.ParseGeneric(strings.TrimSuffix(path.Base(file), ".sql"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot parse version of schema upgrade %s", file)
+		}
+
+		ddl, err := fs.ReadFile(upgrades, file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot read schema upgrade %s", file)
+		}
+
+		schemaUpgrades = append(schemaUpgrades, SchemaUpgrade{Version: v, DDL: string(ddl)})
+	}
+
+	sort.Slice(schemaUpgrades, func(i, j int) bool {
+		return schemaUpgrades[i].Version.LessThan(schemaUpgrades[j].Version)
+	})
+
+	return schemaUpgrades, nil
+}
+
+// UpgradeSchema applies all upgrade scripts from the given file system which are newer than
+// the current schema version from and not newer than the expected schema version to, in order.
+// Each upgrade is executed in its own transaction and its outcome is recorded in the kubernetes_schema table.
+// Note that MySQL implicitly commits most DDL statements, so a failed upgrade may be applied partially there.
+// In any case, the failed upgrade is recorded with the reason of its failure.
+// On MySQL, a previously failed upgrade is not retried, as its statements would fail on the objects
+// it has already created. Instead, an error describing the manual recovery is returned.
+func (db *Database) UpgradeSchema(ctx context.Context, upgrades fs.FS, from, to string) error {
+	current, err := version.ParseGeneric(from)
+	if err != nil {
+		return errors.Wrapf(err, "cannot parse current schema version %q", from)
+	}
+
+	expected, err := version.ParseGeneric(to)
+	if err != nil {
+		return errors.Wrapf(err, "cannot parse expected schema version %q", to)
+	}
+
+	if expected.LessThan(current) {
+		return errors.Errorf("database schema version %s is newer than the expected version %s", from, to)
+	}
+
+	schemaUpgrades, err := SchemaUpgrades(upgrades)
+	if err != nil {
+		return err
+	}
+
+	var pending []SchemaUpgrade
+	for _, upgrade := range schemaUpgrades {
+		if current.LessThan(upgrade.Version) && !expected.LessThan(upgrade.Version) {
+			pending = append(pending, upgrade)
+		}
+	}
+
+	if len(pending) == 0 || !pending[len(pending)-1].Version.EqualTo(expected) {
+		return errors.Errorf("no schema upgrade path from version %s to %s", from, to)
+	}
+
+	if db.DriverName() == MySQL {
+		if err := db.checkFailedSchemaUpgrade(ctx, pending[0]); err != nil {
+			return err
+		}
+	}
+
+	for _, upgrade := range pending {
+		db.log.Info("Upgrading schema", "from", current.String(), "to", upgrade.Version.String())
+
+		if err := db.applySchemaUpgrade(ctx, upgrade, current); err != nil {
+			record := schemaVersion{
+				Version:   upgrade.Version.String(),
+				Timestamp: types.UnixMilli(time.Now()),
+				Success:   types.Bool{Bool: false, Valid: true},
+				Reason:    sql.NullString{String: err.Error(), Valid: true},
+			}
+
+			stmt, _ := db.BuildUpsertStmt(record)
+			if _, rerr := db.NamedExecContext(ctx, stmt, record); rerr != nil {
+				db.log.Error(rerr, "cannot record failed schema upgrade", "version", record.Version)
+			}
+
+			return errors.Wrapf(err, "cannot upgrade schema to version %s", upgrade.Version)
+		}
+
+		current = upgrade.Version
+	}
+
+	return nil
+}
+
+// checkFailedSchemaUpgrade returns an error if the given upgrade has failed before,
+// in which case it may have been applied partially and must be recovered manually.
+func (db *Database) checkFailedSchemaUpgrade(ctx context.Context, upgrade SchemaUpgrade) error {
+	var reason sql.NullString
+	err := db.QueryRowxContext(
+		ctx,
+		db.Rebind("SELECT reason FROM kubernetes_schema WHERE version = ? AND success = 'n'"),
+		upgrade.Version.String(),
+	).Scan(&reason)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "cannot check for failed schema upgrades")
+	}
+
+	return errors.Errorf(
+		"a previous upgrade of the database schema to version %[1]s failed: %[2]s. "+
+			"As MySQL commits schema changes implicitly, it may have been applied partially and cannot be retried. "+
+			"Either restore the database from a backup taken before the upgrade and start again, "+
+			"or apply the remaining statements of schema/mysql/upgrades/%[1]s.sql manually and then run "+
+			"UPDATE kubernetes_schema SET success = 'y' WHERE version = '%[1]s'",
+		upgrade.Version, reason.String)
+}
+
+// applySchemaUpgrade executes the statements of the given upgrade and
+// records the new schema version in a single transaction.
+func (db *Database) applySchemaUpgrade(ctx context.Context, upgrade SchemaUpgrade, from *version.Version) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "cannot start transaction")
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, ddl := range strings.Split(upgrade.DDL, ";") {
+		if ddl = strings.TrimSpace(ddl); ddl != "" {
+			if _, err := tx.ExecContext(ctx, ddl); err != nil {
+				return CantPerformQuery(err, ddl)
+			}
+		}
+	}
+
+	record := schemaVersion{
+		Version:   upgrade.Version.String(),
+		Timestamp: types.UnixMilli(time.Now()),
+		Success:   types.Bool{Bool: true, Valid: true},
+		Reason:    sql.NullString{String: "Upgrade from " + from.String(), Valid: true},
+	}
+
+	stmt, _ := db.BuildUpsertStmt(record)
+	if _, err := tx.NamedExecContext(ctx, stmt, record); err != nil {
+		return CantPerformQuery(err, stmt)
+	}
+
+	return errors.Wrap(tx.Commit(), "cannot commit transaction")
+}
+
+// schemaVersion represents a row of the kubernetes_schema table.
+type schemaVersion struct {
+	Version   string
+	Timestamp types.UnixMilli
+	Success   types.Bool
+	Reason    sql.NullString
+}
+
+// TableName implements the TableNamer interface.
+func (schemaVersion) TableName() string {
+	return "kubernetes_schema"
+}
+
+// PgsqlOnConflictConstraint implements the PgsqlOnConflictConstrainter interface.
+func (schemaVersion) PgsqlOnConflictConstraint() string {
+	return "idx_kubernetes_schema_version"
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"github.com/icinga/icinga-kubernetes/pkg/com"
+	"github.com/pkg/errors"
+	promapi "github.com/prometheus/client_golang/api"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	ktransport "k8s.io/client-go/transport"
+	"net/http"
+	"net/url"
+)
+
+// serviceAccountTokenFile is the file in which Kubernetes mounts the token of the service account of a pod.
+const serviceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// NewPromApiClient creates a Prometheus API client from the given configuration,
+// which authenticates with basic auth, a bearer token or a TLS client certificate if configured.
+func NewPromApiClient(c *PrometheusConfig) (v1.API, error) {
+	u, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse Prometheus URL")
+	}
+
+	tlsConfig, err := c.TlsOptions.MakeConfig(u.Hostname())
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create TLS configuration for Prometheus")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	var roundTripper http.RoundTripper = transport
+
+	tokenFile := c.BearerTokenFile
+	if c.ServiceAccountToken {
+		tokenFile = serviceAccountTokenFile
+	}
+
+	switch {
+	case c.Username != "" && c.Password != "":
+		roundTripper = &com.BasicAuthTransport{
+			RoundTripper: roundTripper,
+			Username:     c.Username,
+			Password:     c.Password,
+		}
+	case c.BearerToken != "" || tokenFile != "":
+		roundTripper, err = ktransport.NewBearerAuthWithRefreshRoundTripper(c.BearerToken, tokenFile, roundTripper)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot read Prometheus bearer token file")
+		}
+	}
+
+	if len(c.Headers) > 0 {
+		roundTripper = &com.HeaderTransport{RoundTripper: roundTripper, Headers: c.Headers}
+	}
+
+	client, err := promapi.NewClient(promapi.Config{
+		Address:      c.Url,
+		RoundTripper: roundTripper,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating Prometheus client")
+	}
+
+	return v1.NewAPI(client), nil
+}
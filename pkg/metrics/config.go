@@ -1,14 +1,108 @@
 package metrics
 
 import (
+	"github.com/icinga/icinga-go-library/config"
 	"github.com/pkg/errors"
+	"slices"
 )
 
+// Metric categories, each of which is synchronized from Prometheus into its own table.
+const (
+	CategoryCluster   = "cluster"
+	CategoryNode      = "node"
+	CategoryPod       = "pod"
+	CategoryContainer = "container"
+)
+
+// Categories lists all valid metric categories.
+var Categories = []string{
+	CategoryCluster,
+	CategoryNode,
+	CategoryPod,
+	CategoryContainer,
+}
+
 // PrometheusConfig defines Prometheus configuration.
 type PrometheusConfig struct {
 	Url      string `yaml:"url"`
 	Username string `yaml:"username"`
 	Password string `yaml:"password"`
+	// BearerToken is sent in the Authorization header of all requests.
+	BearerToken string `yaml:"bearer_token"`
+	// BearerTokenFile is read periodically for the bearer token, so that rotated tokens are picked up.
+	BearerTokenFile string `yaml:"bearer_token_file"`
+	// ServiceAccountToken uses the token of the service account of the pod as bearer token.
+	ServiceAccountToken bool `yaml:"service_account_token"`
+	// Headers are set on all requests, e.g. for authentication proxies.
+	Headers    map[string]string `yaml:"headers"`
+	TlsOptions config.TLS        `yaml:",inline"`
+	// Metrics enables or disables the synchronization of individual categories, e.g. container: false.
+	// Categories that are not configured are synchronized.
+	Metrics map[string]bool `yaml:"metrics"`
+	// Queries defines additional queries per category or overrides the predefined queries of the same metric category.
+	Queries map[string][]QueryConfig `yaml:"queries"`
+	// Thresholds defines from which values on the metrics of nodes and pods make them warning or critical.
+	Thresholds ThresholdsConfig `yaml:"thresholds"`
+}
+
+// QueryConfig defines a Prometheus query whose results are synchronized as metrics of the given metric category.
+type QueryConfig struct {
+	// Category is the metric category, e.g. cpu.usage, which overrides the predefined query of the same category.
+	Category string `yaml:"category"`
+	// Query is the PromQL query. Its results must have the labels that identify the objects of the category,
+	// e.g. namespace and pod for pods.
+	Query string `yaml:"query"`
+	// NameLabel is the label whose value is used as the name of the metric, which distinguishes multiple results
+	// of the query per object.
+	NameLabel string `yaml:"name_label"`
+}
+
+// Validate checks constraints in the supplied query configuration and returns an error if they are violated.
+func (c *QueryConfig) Validate() error {
+	if c.Category == "" {
+		return errors.New("category missing")
+	}
+
+	if len(c.Category) > 255 {
+		return errors.Errorf("category %q must not be longer than 255 characters", c.Category)
+	}
+
+	if c.Query == "" {
+		return errors.Errorf("query missing for metric category %q", c.Category)
+	}
+
+	return nil
+}
+
+// ConnectionConfigured returns whether any option of the connection to Prometheus is configured.
+func (c *PrometheusConfig) ConnectionConfigured() bool {
+	return c.Url != "" || c.Username != "" || c.Password != "" || c.BearerToken != "" || c.BearerTokenFile != "" ||
+		c.ServiceAccountToken || len(c.Headers) > 0 || c.TlsOptions.Enable
+}
+
+// Inherit returns a copy of the configuration that uses the metric categories, queries and thresholds of
+// the given parent configuration unless it defines its own. The connection to Prometheus is never inherited.
+func (c PrometheusConfig) Inherit(parent *PrometheusConfig) PrometheusConfig {
+	if c.Metrics == nil {
+		c.Metrics = parent.Metrics
+	}
+
+	if c.Queries == nil {
+		c.Queries = parent.Queries
+	}
+
+	if !c.Thresholds.Enabled() {
+		c.Thresholds = parent.Thresholds
+	}
+
+	return c
+}
+
+// Enabled returns whether metrics of the given category are synchronized.
+func (c *PrometheusConfig) Enabled(category string) bool {
+	enabled, ok := c.Metrics[category]
+
+	return !ok || enabled
 }
 
 // Validate checks constraints in the supplied Prometheus configuration and returns an error if they are violated.
@@ -17,5 +111,52 @@ func (c *PrometheusConfig) Validate() error {
 		return errors.New("both username and password must be provided")
 	}
 
+	var authMethods int
+	for _, set := range []bool{
+		c.Username != "", c.BearerToken != "", c.BearerTokenFile != "", c.ServiceAccountToken,
+	} {
+		if set {
+			authMethods++
+		}
+	}
+	if authMethods > 1 {
+		return errors.New(
+			"only one of username and password, bearer_token, bearer_token_file and service_account_token can be set")
+	}
+
+	if !c.TlsOptions.Enable &&
+		(c.TlsOptions.Cert != "" || c.TlsOptions.Key != "" || c.TlsOptions.Ca != "" || c.TlsOptions.Insecure) {
+		return errors.New("tls must be enabled to use cert, key, ca or insecure")
+	}
+
+	for category := range c.Metrics {
+		if !slices.Contains(Categories, category) {
+			return errors.Errorf("invalid metric category %q, must be one of %v", category, Categories)
+		}
+	}
+
+	for category, queries := range c.Queries {
+		if !slices.Contains(Categories, category) {
+			return errors.Errorf("invalid query category %q, must be one of %v", category, Categories)
+		}
+
+		metricCategories := make(map[string]struct{}, len(queries))
+		for i := range queries {
+			if err := queries[i].Validate(); err != nil {
+				return errors.Wrapf(err, "invalid %s query", category)
+			}
+
+			if _, ok := metricCategories[queries[i].Category]; ok {
+				return errors.Errorf("%s query metric category %q must be unique", category, queries[i].Category)
+			}
+
+			metricCategories[queries[i].Category] = struct{}{}
+		}
+	}
+
+	if err := c.Thresholds.Validate(); err != nil {
+		return errors.Wrap(err, "invalid thresholds")
+	}
+
 	return nil
 }
@@ -2,7 +2,6 @@ package metrics
 
 import (
 	"context"
-	"fmt"
 	"github.com/icinga/icinga-go-library/backoff"
 	"github.com/icinga/icinga-go-library/database"
 	"github.com/icinga/icinga-go-library/logging"
@@ -185,6 +184,11 @@ var (
 			`sum by (namespace, pod) (container_memory_usage_bytes)`,
 			"",
 		},
+		{
+			"memory.limit.usage",
+			`max by (namespace, pod) (sum by (namespace, pod, container) (container_memory_working_set_bytes{container!="", container!="POD"}) / on (namespace, pod, container) sum by (namespace, pod, container) (kube_pod_container_resource_limits{resource="memory"}))`,
+			"",
+		},
 		{
 			"cpu.request",
 			`sum by (node, namespace, pod) (kube_pod_container_resource_requests{resource="cpu"})`,
@@ -268,6 +272,16 @@ var (
 			`sum by (node, namespace, pod, container) (kube_pod_container_resource_limits{resource="memory"}) / on(node) group_left() (sum by (node) (machine_memory_bytes))`,
 			"",
 		},
+		{
+			"cpu.usage.cores",
+			`sum by (namespace, pod, container) (rate(container_cpu_usage_seconds_total{container!="", container!="POD"}[2m]))`,
+			"",
+		},
+		{
+			"memory.usage.bytes",
+			`sum by (namespace, pod, container) (container_memory_working_set_bytes{container!="", container!="POD"})`,
+			"",
+		},
 	}
 )
 
@@ -275,60 +289,91 @@ var (
 type PromMetricSync struct {
 	promApiClient v1.API
 	db            *database.DB
+	clusterUuid   types.UUID
+	queries       map[string][]PromQuery
+	samples       *Samples
 	logger        *logging.Logger
 }
 
-// NewPromMetricSync creates a new PromMetricSync
-func NewPromMetricSync(promApiClient v1.API, db *database.DB, logger *logging.Logger) *PromMetricSync {
+// NewPromMetricSync creates a new PromMetricSync that runs the predefined queries
+// together with the given queries per category, which override predefined queries of the same metric category.
+// The latest metric values of nodes and pods are also added to the given samples, if not nil.
+func NewPromMetricSync(
+	promApiClient v1.API,
+	db *database.DB,
+	clusterUuid types.UUID,
+	queries map[string][]QueryConfig,
+	samples *Samples,
+	logger *logging.Logger,
+) *PromMetricSync {
 	return &PromMetricSync{
 		promApiClient: promApiClient,
 		db:            db,
-		logger:        logger,
+		clusterUuid:   clusterUuid,
+		queries: map[string][]PromQuery{
+			CategoryCluster:   mergePromQueries(promQueriesCluster, queries[CategoryCluster]),
+			CategoryNode:      mergePromQueries(promQueriesNode, queries[CategoryNode]),
+			CategoryPod:       mergePromQueries(promQueriesPod, queries[CategoryPod]),
+			CategoryContainer: mergePromQueries(promQueriesContainer, queries[CategoryContainer]),
+		},
+		samples: samples,
+		logger:  logger,
 	}
 }
 
+// mergePromQueries returns the given predefined queries, of which those with the metric category of
+// a configured query are replaced by it, followed by the remaining configured queries.
+func mergePromQueries(predefined []PromQuery, configured []QueryConfig) []PromQuery {
+	queries := make([]PromQuery, 0, len(predefined)+len(configured))
+	overrides := make(map[string]PromQuery, len(configured))
+	for _, c := range configured {
+		overrides[c.Category] = PromQuery{c.Category, c.Query, model.LabelName(c.NameLabel)}
+	}
+
+	for _, q := range predefined {
+		if o, ok := overrides[q.metricCategory]; ok {
+			q = o
+			delete(overrides, q.metricCategory)
+		}
+
+		queries = append(queries, q)
+	}
+
+	for _, c := range configured {
+		if o, ok := overrides[c.Category]; ok {
+			queries = append(queries, o)
+		}
+	}
+
+	return queries
+}
+
 // promMetricClusterUpsertStmt returns database upsert statement to upsert cluster metrics
 func (pms *PromMetricSync) promMetricClusterUpsertStmt() string {
-	return fmt.Sprintf(
-		`INSERT INTO %s (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s`,
-		`prometheus_cluster_metric`,
-		"cluster_id, timestamp, category, name, value",
-		`:cluster_id, :timestamp, :category, :name, :value`,
-		`value=VALUES(value)`,
-	)
+	stmt, _ := pms.db.BuildUpsertStmt(&schemav1.PrometheusClusterMetric{})
+
+	return stmt
 }
 
 // promMetricNodeUpsertStmt returns database upsert statement to upsert node metrics
 func (pms *PromMetricSync) promMetricNodeUpsertStmt() string {
-	return fmt.Sprintf(
-		`INSERT INTO %s (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s`,
-		`prometheus_node_metric`,
-		"node_uuid, timestamp, category, name, value",
-		`:node_uuid, :timestamp, :category, :name, :value`,
-		`value=VALUES(value)`,
-	)
+	stmt, _ := pms.db.BuildUpsertStmt(&schemav1.PrometheusNodeMetric{})
+
+	return stmt
 }
 
 // promMetricPodUpsertStmt returns database upsert statement to upsert pod metrics
 func (pms *PromMetricSync) promMetricPodUpsertStmt() string {
-	return fmt.Sprintf(
-		`INSERT INTO %s (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s`,
-		`prometheus_pod_metric`,
-		"pod_uuid, timestamp, category, name, value",
-		`:pod_uuid, :timestamp, :category, :name, :value`,
-		`value=VALUES(value)`,
-	)
+	stmt, _ := pms.db.BuildUpsertStmt(&schemav1.PrometheusPodMetric{})
+
+	return stmt
 }
 
 // promMetricContainerUpsertStmt returns database upsert statement to upsert container metrics
 func (pms *PromMetricSync) promMetricContainerUpsertStmt() string {
-	return fmt.Sprintf(
-		`INSERT INTO %s (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s`,
-		`prometheus_container_metric`,
-		"container_id, timestamp, category, name, value",
-		`:container_id, :timestamp, :category, :name, :value`,
-		`value=VALUES(value)`,
-	)
+	stmt, _ := pms.db.BuildUpsertStmt(&schemav1.PrometheusContainerMetric{})
+
+	return stmt
 }
 
 func (pms *PromMetricSync) run(
@@ -441,7 +486,7 @@ func (pms *PromMetricSync) Nodes(ctx context.Context, informer kcache.SharedInde
 	g.Go(func() error {
 		return pms.run(
 			ctx,
-			promQueriesNode,
+			pms.queries[CategoryNode],
 			upsertMetrics,
 			func(query PromQuery, res *model.Sample) database.Entity {
 				if res.Value.String() == "NaN" {
@@ -478,6 +523,10 @@ func (pms *PromMetricSync) Nodes(ctx context.Context, informer kcache.SharedInde
 					Value:     float64(res.Value),
 				}
 
+				if pms.samples != nil {
+					pms.samples.add(newNodeMetric.NodeUuid, query.metricCategory, name, float64(res.Value), res.Timestamp.Time())
+				}
+
 				return newNodeMetric
 			},
 		)
@@ -502,7 +551,7 @@ func (pms *PromMetricSync) Pods(ctx context.Context, informer kcache.SharedIndex
 	g.Go(func() error {
 		return pms.run(
 			ctx,
-			promQueriesPod,
+			pms.queries[CategoryPod],
 			upsertMetrics,
 			func(query PromQuery, res *model.Sample) database.Entity {
 				if res.Metric["pod"] == "" {
@@ -533,6 +582,10 @@ func (pms *PromMetricSync) Pods(ctx context.Context, informer kcache.SharedIndex
 					Value:     float64(res.Value),
 				}
 
+				if pms.samples != nil {
+					pms.samples.add(newPodMetric.PodUuid, query.metricCategory, name, float64(res.Value), res.Timestamp.Time())
+				}
+
 				return newPodMetric
 			},
 		)
@@ -545,9 +598,10 @@ func (pms *PromMetricSync) Pods(ctx context.Context, informer kcache.SharedIndex
 	return g.Wait()
 }
 
+// Containers synchronizes the metrics of the containers of the pods in the store of the given pod informer.
 func (pms *PromMetricSync) Containers(ctx context.Context, informer kcache.SharedIndexInformer) error {
 	if !kcache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
-		pms.logger.Fatal("timed out waiting for caches to sync")
+		return errors.New("timed out waiting for caches to sync")
 	}
 
 	upsertMetrics := make(chan database.Entity)
@@ -557,14 +611,19 @@ func (pms *PromMetricSync) Containers(ctx context.Context, informer kcache.Share
 	g.Go(func() error {
 		return pms.run(
 			ctx,
-			promQueriesContainer,
+			pms.queries[CategoryContainer],
 			upsertMetrics,
 			func(query PromQuery, res *model.Sample) database.Entity {
-				if res.Value.String() == "NaN" {
+				if res.Value.String() == "NaN" || res.Metric["pod"] == "" || res.Metric["container"] == "" {
 					return nil
 				}
 
-				//containerId := sha1.Sum([]byte(res.Metric["namespace"] + "/" + res.Metric["pod"] + "/" + res.Metric["container"]))
+				obj, exists, err := informer.GetStore().GetByKey(
+					kcache.NewObjectName(string(res.Metric["namespace"]), string(res.Metric["pod"])).String())
+				if err != nil || !exists {
+					return nil
+				}
+				pod := obj.(*kcorev1.Pod)
 
 				name := ""
 
@@ -573,11 +632,11 @@ func (pms *PromMetricSync) Containers(ctx context.Context, informer kcache.Share
 				}
 
 				newContainerMetric := &schemav1.PrometheusContainerMetric{
-					// TODO uuid
-					Timestamp: (res.Timestamp.UnixNano() - res.Timestamp.UnixNano()%(60*1000000000)) / 1000000,
-					Category:  query.metricCategory,
-					Name:      name,
-					Value:     float64(res.Value),
+					ContainerUuid: schemav1.NewUUID(schemav1.EnsureUUID(pod.UID), string(res.Metric["container"])),
+					Timestamp:     (res.Timestamp.UnixNano() - res.Timestamp.UnixNano()%(60*1000000000)) / 1000000,
+					Category:      query.metricCategory,
+					Name:          name,
+					Value:         float64(res.Value),
 				}
 
 				return newContainerMetric
@@ -592,11 +651,8 @@ func (pms *PromMetricSync) Containers(ctx context.Context, informer kcache.Share
 	return g.Wait()
 }
 
-func (pms *PromMetricSync) Clusters(ctx context.Context, informer kcache.SharedIndexInformer) error {
-	if !kcache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
-		pms.logger.Fatal("timed out waiting for caches to sync")
-	}
-
+// Clusters synchronizes the metrics of the cluster.
+func (pms *PromMetricSync) Clusters(ctx context.Context) error {
 	upsertMetrics := make(chan database.Entity)
 
 	g, ctx := errgroup.WithContext(ctx)
@@ -604,15 +660,13 @@ func (pms *PromMetricSync) Clusters(ctx context.Context, informer kcache.SharedI
 	g.Go(func() error {
 		return pms.run(
 			ctx,
-			promQueriesCluster,
+			pms.queries[CategoryCluster],
 			upsertMetrics,
 			func(query PromQuery, res *model.Sample) database.Entity {
 				if res.Value.String() == "NaN" {
 					return nil
 				}
 
-				//clusterId := sha1.Sum([]byte(""))
-
 				name := ""
 
 				if query.nameLabel != "" {
@@ -620,11 +674,11 @@ func (pms *PromMetricSync) Clusters(ctx context.Context, informer kcache.SharedI
 				}
 
 				newClusterMetric := &schemav1.PrometheusClusterMetric{
-					// TODO uuid
-					Timestamp: (res.Timestamp.UnixNano() - res.Timestamp.UnixNano()%(60*1000000000)) / 1000000,
-					Category:  query.metricCategory,
-					Name:      name,
-					Value:     float64(res.Value),
+					ClusterUuid: pms.clusterUuid,
+					Timestamp:   (res.Timestamp.UnixNano() - res.Timestamp.UnixNano()%(60*1000000000)) / 1000000,
+					Category:    query.metricCategory,
+					Name:        name,
+					Value:       float64(res.Value),
 				}
 
 				return newClusterMetric
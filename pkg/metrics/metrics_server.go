@@ -0,0 +1,242 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"github.com/icinga/icinga-go-library/database"
+	"github.com/icinga/icinga-go-library/logging"
+	"github.com/icinga/icinga-go-library/types"
+	"github.com/icinga/icinga-go-library/utils"
+	schemav1 "github.com/icinga/icinga-kubernetes/pkg/schema/v1"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	kcorev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kdiscovery "k8s.io/client-go/discovery"
+	kcache "k8s.io/client-go/tools/cache"
+	kmetricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	kmetrics "k8s.io/metrics/pkg/client/clientset/versioned"
+	"slices"
+	"time"
+)
+
+// metricsServerInterval is how often the metrics API is queried.
+const metricsServerInterval = time.Minute
+
+// metricsServerRetention is how long metrics are retained after they have last been reported,
+// i.e. after their pods or nodes have been deleted.
+const metricsServerRetention = 5 * time.Minute
+
+// MetricsApiAvailable returns whether the metrics API, which is usually served by metrics-server, is available.
+func MetricsApiAvailable(client kdiscovery.DiscoveryInterface) (bool, error) {
+	_, err := client.ServerResourcesForGroupVersion(kmetricsv1beta1.SchemeGroupVersion.String())
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, errors.Wrap(err, "cannot discover metrics API")
+	}
+
+	return true, nil
+}
+
+// MetricsServerSync synchronizes the resource usage of pods and nodes from the metrics API to the database,
+// which is used instead of Prometheus if there is none.
+type MetricsServerSync struct {
+	client      kmetrics.Interface
+	db          *database.DB
+	clusterUuid types.UUID
+	samples     *Samples
+	logger      *logging.Logger
+}
+
+// NewMetricsServerSync creates a new MetricsServerSync.
+// The resource usage of nodes and pods is also added to the given samples, if not nil,
+// in the metric categories of the predefined Prometheus queries, so that thresholds work without Prometheus as well.
+func NewMetricsServerSync(
+	client kmetrics.Interface, db *database.DB, clusterUuid types.UUID, samples *Samples, logger *logging.Logger,
+) *MetricsServerSync {
+	return &MetricsServerSync{
+		client:      client,
+		db:          db,
+		clusterUuid: clusterUuid,
+		samples:     samples,
+		logger:      logger,
+	}
+}
+
+// Pods periodically synchronizes the resource usage of the containers of the pods in the given namespace,
+// or in all namespaces except the excluded ones, whose labels match the given selector.
+// The pods are looked up in the given store for their samples.
+func (mss *MetricsServerSync) Pods(
+	ctx context.Context, namespace, labelSelector string, excludeNamespaces []string, pods kcache.Store,
+) error {
+	return mss.run(ctx, &schemav1.PodMetrics{}, namespace, func(ctx context.Context) ([]database.Entity, error) {
+		list, err := mss.client.MetricsV1beta1().PodMetricses(namespace).List(
+			ctx, kmetav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot list pod metrics")
+		}
+
+		var entities []database.Entity
+		for _, pod := range list.Items {
+			if slices.Contains(excludeNamespaces, pod.Namespace) {
+				continue
+			}
+
+			mss.addPodSamples(pods, &pod)
+
+			for _, container := range pod.Containers {
+				entities = append(entities, &schemav1.PodMetrics{
+					ClusterUuid:           mss.clusterUuid,
+					Namespace:             pod.Namespace,
+					PodName:               pod.Name,
+					ContainerName:         container.Name,
+					Timestamp:             types.UnixMilli(pod.Timestamp.Time),
+					Duration:              pod.Window.Duration,
+					CPUUsage:              container.Usage.Cpu().AsApproximateFloat64(),
+					MemoryUsage:           container.Usage.Memory().AsApproximateFloat64(),
+					StorageUsage:          container.Usage.Storage().AsApproximateFloat64(),
+					EphemeralStorageUsage: container.Usage.StorageEphemeral().AsApproximateFloat64(),
+				})
+			}
+		}
+
+		return entities, nil
+	})
+}
+
+// Nodes periodically synchronizes the resource usage of the nodes whose labels match the given selector.
+// The nodes are looked up in the given store for their samples.
+func (mss *MetricsServerSync) Nodes(ctx context.Context, labelSelector string, nodes kcache.Store) error {
+	return mss.run(ctx, &schemav1.NodeMetrics{}, "", func(ctx context.Context) ([]database.Entity, error) {
+		list, err := mss.client.MetricsV1beta1().NodeMetricses().List(
+			ctx, kmetav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot list node metrics")
+		}
+
+		entities := make([]database.Entity, 0, len(list.Items))
+		for _, node := range list.Items {
+			mss.addNodeSamples(nodes, &node)
+
+			entities = append(entities, &schemav1.NodeMetrics{
+				ClusterUuid: mss.clusterUuid,
+				NodeName:    node.Name,
+				Timestamp:   types.UnixMilli(node.Timestamp.Time),
+				Duration:    node.Window.Duration,
+				CPUUsage:    node.Usage.Cpu().AsApproximateFloat64(),
+				MemoryUsage: node.Usage.Memory().AsApproximateFloat64(),
+			})
+		}
+
+		return entities, nil
+	})
+}
+
+// addNodeSamples adds the CPU and memory usage of the given node relative to its capacity to the samples,
+// which corresponds to the cpu.usage and memory.usage queries of nodes.
+func (mss *MetricsServerSync) addNodeSamples(nodes kcache.Store, metrics *kmetricsv1beta1.NodeMetrics) {
+	if mss.samples == nil {
+		return
+	}
+
+	obj, exists, err := nodes.GetByKey(metrics.Name)
+	if err != nil || !exists {
+		return
+	}
+	node := obj.(*kcorev1.Node)
+	uuid := schemav1.EnsureUUID(node.UID)
+
+	if capacity := node.Status.Capacity.Cpu().AsApproximateFloat64(); capacity > 0 {
+		mss.samples.add(
+			uuid, "cpu.usage", "", metrics.Usage.Cpu().AsApproximateFloat64()/capacity, metrics.Timestamp.Time)
+	}
+
+	if capacity := node.Status.Capacity.Memory().AsApproximateFloat64(); capacity > 0 {
+		mss.samples.add(
+			uuid, "memory.usage", "", metrics.Usage.Memory().AsApproximateFloat64()/capacity, metrics.Timestamp.Time)
+	}
+}
+
+// addPodSamples adds the CPU and memory usage of the given pod to the samples, which corresponds to
+// the cpu.usage.cores, memory.usage.bytes and memory.limit.usage queries of pods.
+func (mss *MetricsServerSync) addPodSamples(pods kcache.Store, metrics *kmetricsv1beta1.PodMetrics) {
+	if mss.samples == nil {
+		return
+	}
+
+	obj, exists, err := pods.GetByKey(kcache.NewObjectName(metrics.Namespace, metrics.Name).String())
+	if err != nil || !exists {
+		return
+	}
+	pod := obj.(*kcorev1.Pod)
+	uuid := schemav1.EnsureUUID(pod.UID)
+
+	limits := make(map[string]float64, len(pod.Spec.Containers))
+	for _, container := range pod.Spec.Containers {
+		if limit := container.Resources.Limits.Memory().AsApproximateFloat64(); limit > 0 {
+			limits[container.Name] = limit
+		}
+	}
+
+	var cpu, memory, limitUsage float64
+	var limited bool
+	for _, container := range metrics.Containers {
+		cpu += container.Usage.Cpu().AsApproximateFloat64()
+		memory += container.Usage.Memory().AsApproximateFloat64()
+
+		if limit, ok := limits[container.Name]; ok {
+			limitUsage = max(limitUsage, container.Usage.Memory().AsApproximateFloat64()/limit)
+			limited = true
+		}
+	}
+
+	mss.samples.add(uuid, "cpu.usage.cores", "", cpu, metrics.Timestamp.Time)
+	mss.samples.add(uuid, "memory.usage.bytes", "", memory, metrics.Timestamp.Time)
+	if limited {
+		mss.samples.add(uuid, "memory.limit.usage", "", limitUsage, metrics.Timestamp.Time)
+	}
+}
+
+// run periodically collects metrics via the given function, upserts them into the table of the given entity
+// and deletes the metrics of the table that have not been reported for a while. If namespace is not empty,
+// only the metrics of that namespace are deleted. Errors of the metrics API are logged and retried in the next period.
+func (mss *MetricsServerSync) run(
+	ctx context.Context,
+	entity database.Entity,
+	namespace string,
+	collect func(ctx context.Context) ([]database.Entity, error),
+) error {
+	for {
+		entities, err := collect(ctx)
+		if err != nil {
+			mss.logger.Warnw("Cannot query metrics API", zap.Error(err))
+		} else {
+			if len(entities) > 0 {
+				if err := database.NewUpsert(mss.db).Stream(ctx, utils.ChanFromSlice(entities)); err != nil {
+					return errors.Wrap(err, "cannot upsert metrics")
+				}
+			}
+
+			query := fmt.Sprintf(`DELETE FROM %s WHERE cluster_uuid = ? AND timestamp < ?`, database.TableName(entity))
+			args := []any{mss.clusterUuid, types.UnixMilli(time.Now().Add(-metricsServerRetention))}
+			if namespace != kmetav1.NamespaceAll {
+				query += ` AND namespace = ?`
+				args = append(args, namespace)
+			}
+
+			if _, err := mss.db.ExecContext(ctx, mss.db.Rebind(query), args...); err != nil {
+				return errors.Wrap(err, "cannot delete outdated metrics")
+			}
+		}
+
+		select {
+		case <-time.After(metricsServerInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package metrics
+
+import (
+	"fmt"
+	"github.com/icinga/icinga-go-library/types"
+	schemav1 "github.com/icinga/icinga-kubernetes/pkg/schema/v1"
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ThresholdAnnotationPrefix prefixes annotations that override the thresholds of the metric category that follows it,
+// e.g. icinga.com/threshold.memory.usage: warning=0.8,critical=0.9. An empty value disables the thresholds.
+const ThresholdAnnotationPrefix = "icinga.com/threshold."
+
+// sampleMaxAge is how long a sample is evaluated after it has been collected,
+// so that metrics that are no longer reported do not affect Icinga states anymore.
+const sampleMaxAge = 5 * time.Minute
+
+// Threshold defines the values of a metric from which on it is warning or critical.
+type Threshold struct {
+	Warning  *float64 `yaml:"warning"`
+	Critical *float64 `yaml:"critical"`
+}
+
+// Validate checks constraints in the supplied threshold and returns an error if they are violated.
+func (t *Threshold) Validate() error {
+	if t.Warning == nil && t.Critical == nil {
+		return errors.New("either warning or critical must be set")
+	}
+
+	if t.Warning != nil && t.Critical != nil && *t.Warning > *t.Critical {
+		return errors.New("warning must not be greater than critical")
+	}
+
+	return nil
+}
+
+// state returns the Icinga state of the given metric value and the threshold it exceeds, if any.
+func (t *Threshold) state(value float64) (schemav1.IcingaState, float64) {
+	switch {
+	case t.Critical != nil && value >= *t.Critical:
+		return schemav1.Critical, *t.Critical
+	case t.Warning != nil && value >= *t.Warning:
+		return schemav1.Warning, *t.Warning
+	default:
+		return schemav1.Ok, 0
+	}
+}
+
+// parseThreshold parses a threshold of the form warning=0.8,critical=0.9, in which either part can be omitted.
+func parseThreshold(s string) (Threshold, error) {
+	var t Threshold
+	for _, part := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return Threshold{}, errors.Errorf("%q must be of the form key=value", part)
+		}
+
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return Threshold{}, errors.Wrapf(err, "invalid value of %q", key)
+		}
+
+		switch strings.TrimSpace(key) {
+		case "warning":
+			t.Warning = &v
+		case "critical":
+			t.Critical = &v
+		default:
+			return Threshold{}, errors.Errorf("invalid key %q, must be warning or critical", key)
+		}
+	}
+
+	return t, t.Validate()
+}
+
+// ThresholdsConfig defines thresholds per metric category, e.g. filesystem.usage,
+// from which on nodes and pods are warning or critical.
+type ThresholdsConfig struct {
+	Node map[string]Threshold `yaml:"node"`
+	Pod  map[string]Threshold `yaml:"pod"`
+	// Namespaces overrides the thresholds of pods per namespace.
+	Namespaces map[string]map[string]Threshold `yaml:"namespaces"`
+}
+
+// Enabled returns whether any thresholds are configured, which enables their evaluation.
+func (c *ThresholdsConfig) Enabled() bool {
+	return len(c.Node) > 0 || len(c.Pod) > 0 || len(c.Namespaces) > 0
+}
+
+// Validate checks constraints in the supplied configuration and returns an error if they are violated.
+func (c *ThresholdsConfig) Validate() error {
+	for category, threshold := range c.Node {
+		if err := threshold.Validate(); err != nil {
+			return errors.Wrapf(err, "invalid node threshold for metric category %q", category)
+		}
+	}
+
+	for category, threshold := range c.Pod {
+		if err := threshold.Validate(); err != nil {
+			return errors.Wrapf(err, "invalid pod threshold for metric category %q", category)
+		}
+	}
+
+	for namespace, thresholds := range c.Namespaces {
+		for category, threshold := range thresholds {
+			if err := threshold.Validate(); err != nil {
+				return errors.Wrapf(
+					err, "invalid pod threshold for metric category %q in namespace %q", category, namespace)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sample is a collected metric value.
+type sample struct {
+	category  string
+	name      string
+	value     float64
+	timestamp time.Time
+}
+
+// Samples are the latest metric values of nodes and pods by their UUID, against which thresholds are evaluated.
+type Samples struct {
+	mu      sync.RWMutex
+	samples map[types.UUID]map[string]sample
+	pruned  time.Time
+}
+
+// NewSamples returns new empty Samples.
+func NewSamples() *Samples {
+	return &Samples{samples: make(map[types.UUID]map[string]sample), pruned: time.Now()}
+}
+
+// add stores the given metric value of the object with the given UUID,
+// replacing its previous value of the same category and name.
+func (s *Samples) add(uuid types.UUID, category, name string, value float64, timestamp time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.pruned) >= sampleMaxAge {
+		s.prune()
+	}
+
+	samples, ok := s.samples[uuid]
+	if !ok {
+		samples = make(map[string]sample)
+		s.samples[uuid] = samples
+	}
+
+	samples[category+"/"+name] = sample{category: category, name: name, value: value, timestamp: timestamp}
+}
+
+// prune deletes the samples that are too old to be evaluated, e.g. of deleted objects.
+// The caller must hold the write lock.
+func (s *Samples) prune() {
+	for uuid, samples := range s.samples {
+		maps.DeleteFunc(samples, func(_ string, sample sample) bool {
+			return time.Since(sample.timestamp) >= sampleMaxAge
+		})
+
+		if len(samples) == 0 {
+			delete(s.samples, uuid)
+		}
+	}
+
+	s.pruned = time.Now()
+}
+
+// get returns the samples of the object with the given UUID that are recent enough to be evaluated,
+// sorted by category and name.
+func (s *Samples) get(uuid types.UUID) []sample {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var samples []sample
+	for _, sample := range s.samples[uuid] {
+		if time.Since(sample.timestamp) < sampleMaxAge {
+			samples = append(samples, sample)
+		}
+	}
+
+	slices.SortFunc(samples, func(a, b sample) int {
+		if c := strings.Compare(a.category, b.category); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.name, b.name)
+	})
+
+	return samples
+}
+
+// Thresholds evaluates the latest metric values of nodes and pods against the configured thresholds.
+type Thresholds struct {
+	config  ThresholdsConfig
+	samples *Samples
+}
+
+// NewThresholds returns new Thresholds that evaluates the given samples against the given configuration.
+func NewThresholds(config ThresholdsConfig, samples *Samples) *Thresholds {
+	return &Thresholds{config: config, samples: samples}
+}
+
+// Apply evaluates the latest metric values of the given Kubernetes object, which must be a node or pod,
+// against its thresholds and merges the resulting Icinga state into the state of the given entity,
+// which has been obtained from the object. Its reason is extended by the exceeded thresholds.
+// Objects whose problems are ignored via annotation are not evaluated,
+// and the metric-derived state is capped at the severity cap of the object.
+func (t *Thresholds) Apply(k8s kmetav1.Object, entity schemav1.IcingaStater) {
+	thresholds := make(map[string]Threshold)
+	switch k8s.(type) {
+	case *kcorev1.Node:
+		maps.Copy(thresholds, t.config.Node)
+	case *kcorev1.Pod:
+		maps.Copy(thresholds, t.config.Pod)
+		maps.Copy(thresholds, t.config.Namespaces[k8s.GetNamespace()])
+	default:
+		return
+	}
+
+	annotations := k8s.GetAnnotations()
+	if ignore, err := strconv.ParseBool(annotations[schemav1.IgnoreAnnotation]); err == nil && ignore {
+		return
+	}
+
+	var invalid []string
+	for annotation, value := range annotations {
+		category, ok := strings.CutPrefix(annotation, ThresholdAnnotationPrefix)
+		if !ok {
+			continue
+		}
+
+		if value == "" {
+			delete(thresholds, category)
+
+			continue
+		}
+
+		threshold, err := parseThreshold(value)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s=%q", annotation, value))
+
+			continue
+		}
+
+		thresholds[category] = threshold
+	}
+
+	worst := schemav1.Ok
+	var exceeded []string
+	for _, sample := range t.samples.get(schemav1.EnsureUUID(k8s.GetUID())) {
+		threshold, ok := thresholds[sample.category]
+		if !ok {
+			continue
+		}
+
+		state, limit := threshold.state(sample.value)
+		if state == schemav1.Ok {
+			continue
+		}
+
+		metric := sample.category
+		if sample.name != "" {
+			metric = fmt.Sprintf("%s (%s)", sample.category, sample.name)
+		}
+
+		worst = max(worst, state)
+		exceeded = append(exceeded, fmt.Sprintf(
+			"Metric %s is %g, which exceeds the %s threshold of %g.", metric, sample.value, state, limit))
+	}
+
+	if severityCap, err := schemav1.ParseIcingaState(annotations[schemav1.SeverityCapAnnotation]); err == nil {
+		worst = min(worst, severityCap)
+	}
+
+	if len(exceeded) == 0 && len(invalid) == 0 {
+		return
+	}
+
+	state, reason := entity.GetIcingaState()
+	if len(exceeded) > 0 {
+		state = max(state, worst)
+		reason = strings.TrimSpace(reason + " " + strings.Join(exceeded, " "))
+	}
+
+	if len(invalid) > 0 {
+		slices.Sort(invalid)
+		reason = fmt.Sprintf("%s Ignoring invalid threshold annotations: %s.", reason, strings.Join(invalid, ", "))
+	}
+
+	entity.SetIcingaState(state, reason)
+}
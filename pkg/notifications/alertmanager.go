@@ -0,0 +1,108 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/icinga/icinga-kubernetes/pkg/com"
+	"github.com/pkg/errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// alertmanagerAlertName is the alertname label of all alerts sent to Alertmanager.
+// The objects are distinguished by the other labels, i.e. the tags of the events,
+// which do not change with their state, so that each object has exactly one alert.
+const alertmanagerAlertName = "KubernetesObjectState"
+
+// alertmanagerStateTags are event tags that describe the state of an object rather than the object itself,
+// e.g. the outcome of a rollout. They are annotations of the alerts, as they must not distinguish alerts.
+var alertmanagerStateTags = []string{"rollout"}
+
+// alertmanagerSink sends events as alerts to the v2 API of Alertmanager.
+// Events with severity ok resolve the alert of their object.
+type alertmanagerSink struct {
+	httpSender
+	alertsUrl      string
+	resolveTimeout time.Duration
+}
+
+// alertmanagerAlert is an alert of the Alertmanager v2 API.
+type alertmanagerAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+func newAlertmanagerSink(userAgent string, config *AlertmanagerConfig) (*alertmanagerSink, error) {
+	baseUrl, err := url.Parse(config.Url)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse alertmanager url")
+	}
+
+	transport := http.DefaultTransport
+	if config.Username != "" {
+		transport = &com.BasicAuthTransport{
+			RoundTripper: transport,
+			Username:     config.Username,
+			Password:     config.Password,
+		}
+	}
+
+	return &alertmanagerSink{
+		httpSender: httpSender{
+			client:    http.Client{Transport: transport},
+			userAgent: userAgent,
+		},
+		alertsUrl:      baseUrl.JoinPath("api", "v2", "alerts").String(),
+		resolveTimeout: config.ResolveTimeout,
+	}, nil
+}
+
+// Name implements the Sink interface.
+func (s *alertmanagerSink) Name() string {
+	return "alertmanager"
+}
+
+// Send implements the Sink interface.
+// Alerts of problems end after the resolve timeout unless they are sent again, so that they do not fire forever
+// if this daemon stops. Alerts of objects that are ok end immediately, which resolves them.
+// The severity is an annotation rather than a label, so that a change of severity updates the alert of the object
+// instead of firing a new one.
+func (s *alertmanagerSink) Send(ctx context.Context, e Event) error {
+	labels := map[string]string{"alertname": alertmanagerAlertName}
+	annotations := map[string]string{"severity": e.Severity, "summary": e.Name, "description": e.Message}
+	for name, value := range e.Tags {
+		if slices.Contains(alertmanagerStateTags, name) {
+			annotations[name] = value
+		} else {
+			labels[name] = value
+		}
+	}
+	for name, value := range e.ExtraTags {
+		labels[name] = value
+	}
+
+	endsAt := time.Now()
+	if e.Severity != "ok" {
+		endsAt = endsAt.Add(s.resolveTimeout)
+	}
+
+	alert := alertmanagerAlert{
+		Labels:      labels,
+		Annotations: annotations,
+		EndsAt:      endsAt,
+	}
+	if e.URL != nil {
+		alert.GeneratorURL = e.URL.String()
+	}
+
+	body, err := json.Marshal([]alertmanagerAlert{alert})
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal alertmanager alert")
+	}
+
+	return s.post(ctx, s.alertsUrl, "application/json", body, http.StatusOK)
+}
@@ -1,96 +1,226 @@
 package notifications
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"github.com/icinga/icinga-kubernetes/pkg/com"
+	"github.com/icinga/icinga-go-library/periodic"
+	"github.com/icinga/icinga-go-library/types"
 	"github.com/pkg/errors"
-	"io"
+	"golang.org/x/sync/errgroup"
 	"k8s.io/klog/v2"
-	"net/http"
 	"net/url"
+	"time"
 )
 
+// checkInterval is the interval in which problems are checked for renotification and silences for expiry.
+const checkInterval = time.Minute
+
 type Client struct {
-	client          http.Client
-	userAgent       string
-	processEventUrl string
-	webUrl          *url.URL
+	webUrl      *url.URL
+	cluster     string
+	transitions *transitions
+	silences    *silences
+	deliveries  []*delivery
 }
 
-func NewClient(name string, config Config) (*Client, error) {
-	baseUrl, err := url.Parse(config.Url)
-	if err != nil {
-		return nil, errors.Wrap(err, "unable to parse url")
-	}
-
+// NewClient returns a new Client for the given configuration, whose events are tagged with the given cluster name.
+// Events are sent to every configured sink, i.e. Icinga Notifications, Alertmanager and a webhook.
+// If outbox is not nil, events pending delivery are persisted in it, so that they survive restarts.
+// If silenceStore is not nil, silenced objects are persisted in it, so that their summaries survive restarts.
+func NewClient(name, cluster string, config Config, outbox *Outbox, silenceStore *SilenceStore) (*Client, error) {
 	webUrl, err := url.Parse(config.KubernetesWebUrl)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to parse web url")
 	}
 
-	return &Client{
-		client: http.Client{
-			Transport: &com.BasicAuthTransport{
-				RoundTripper: http.DefaultTransport,
-				Username:     config.Username,
-				Password:     config.Password,
-			},
-		},
-		userAgent:       name,
-		processEventUrl: baseUrl.ResolveReference(&url.URL{Path: "/process-event"}).String(),
-		webUrl:          webUrl,
-	}, nil
-}
+	var sinks []Sink
 
-func (c *Client) ProcessEvent(ctx context.Context, event Marshaler) error {
-	e, _ := event.MarshalEvent()
-	e.URL = c.webUrl.ResolveReference(e.URL)
+	if config.Url != "" {
+		sink, err := newIcingaNotificationsSink(name, &config)
+		if err != nil {
+			return nil, err
+		}
 
-	body, err := json.Marshal(e)
-	if err != nil {
-		return errors.Wrapf(err, "cannot marshal notifications event data of type: %T", e)
+		sinks = append(sinks, sink)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.processEventUrl, bytes.NewReader(body))
-	if err != nil {
-		return errors.Wrap(err, "cannot create new notifications http request")
+	if config.Alertmanager.Url != "" {
+		sink, err := newAlertmanagerSink(name, &config.Alertmanager)
+		if err != nil {
+			return nil, err
+		}
+
+		sinks = append(sinks, sink)
 	}
 
-	req.Header.Add("Content-Type", "application/json")
+	if config.Webhook.Url != "" {
+		sink, err := newWebhookSink(name, &config.Webhook)
+		if err != nil {
+			return nil, err
+		}
 
-	res, err := c.client.Do(req)
-	if err != nil {
-		return errors.Wrap(err, "cannot send notifications event")
+		sinks = append(sinks, sink)
 	}
 
-	defer func() {
-		_ = res.Body.Close()
-	}()
+	deliveries := make([]*delivery, 0, len(sinks))
+	for _, sink := range sinks {
+		deliveries = append(deliveries, newDelivery(sink, config.QueueSize, outbox))
+	}
 
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotAcceptable {
-		_, msg := io.ReadAll(res.Body)
-		return errors.Errorf("received unexpected http status code from Icinga Notifications: %d: %s", res.StatusCode, msg)
+	return &Client{
+		webUrl:      webUrl,
+		cluster:     cluster,
+		transitions: newTransitions(config.RenotifyInterval),
+		silences:    newSilences(silenceStore),
+		deliveries:  deliveries,
+	}, nil
+}
+
+// Sinks returns the names of the sinks events are sent to.
+func (c *Client) Sinks() []string {
+	names := make([]string, 0, len(c.deliveries))
+	for _, d := range c.deliveries {
+		names = append(names, d.sink.Name())
 	}
 
-	return nil
+	return names
+}
+
+// Remember records the given severity of the object with the given UUID as already notified,
+// so that no event is sent for it until its severity changes.
+func (c *Client) Remember(uuid types.UUID, severity string) {
+	c.transitions.remember(uuid.String(), severity)
 }
 
-// Stream consumes the items from the given `entities` chan and triggers a notifications event for each of them.
-func (c *Client) Stream(ctx context.Context, entities <-chan any) error {
-	for {
+// RestoreSilences loads the silenced objects persisted in the silence store, if any,
+// so that their summaries are sent once their silence ends.
+func (c *Client) RestoreSilences(ctx context.Context) error {
+	return c.silences.restore(ctx)
+}
+
+// Stream consumes the items from the given `upserts` chan and queues a notifications event for each of them
+// whose severity has changed since the last event of the same object, which is then delivered by Deliver.
+// A queued event of the same object is replaced by the newer one.
+// Events of silenced objects are suppressed or downgraded, and a summary is queued once their silence ends.
+// The UUIDs from the given `deletes` chan are forgotten, so that their objects no longer take up memory.
+func (c *Client) Stream(ctx context.Context, upserts, deletes <-chan any) error {
+	for upserts != nil || deletes != nil {
 		select {
-		case entity, more := <-entities:
+		case entity, more := <-upserts:
 			if !more {
-				return nil
+				upserts = nil
+
+				continue
 			}
 
-			if err := c.ProcessEvent(ctx, entity.(Marshaler)); err != nil {
+			e, err := c.marshal(entity.(Marshaler))
+			if err != nil {
 				klog.Error(err)
+
+				continue
 			}
+
+			var silence Silence
+			if silencer, ok := entity.(Silencer); ok {
+				silence = silencer.Silence()
+			}
+
+			c.process(ctx, eventKey(e), e, silence)
+		case id, more := <-deletes:
+			if !more {
+				deletes = nil
+
+				continue
+			}
+
+			key := id.(types.UUID).String()
+			c.transitions.forget(key)
+			c.silences.forget(ctx, key)
 		case <-ctx.Done():
 			return ctx.Err()
 		}
 	}
+
+	return nil
+}
+
+// Deliver sends the queued events to all sinks until ctx is done.
+// Each sink has its own queue, so that a sink that is unavailable does not delay the events of the others.
+// If a renotify interval is configured, the events of persistent problems are queued again periodically,
+// unless their objects are silenced. The summaries of objects whose silence has expired are queued as well.
+func (c *Client) Deliver(ctx context.Context) error {
+	defer periodic.Start(ctx, checkInterval, func(tick periodic.Tick) {
+		for _, e := range c.silences.expire(ctx, tick.Time) {
+			c.enqueue(ctx, e)
+		}
+
+		if c.transitions.renotifyInterval > 0 {
+			for _, e := range c.transitions.due() {
+				if !c.silences.active(e.key, tick.Time) {
+					c.enqueue(ctx, e)
+				}
+			}
+		}
+	}).Stop()
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	for _, d := range c.deliveries {
+		g.Go(func() error {
+			return d.run(ctx)
+		})
+	}
+
+	return g.Wait()
+}
+
+// process queues the given event of the object with the given key and own silence if its state has changed,
+// taking into account the silence of the object or its namespace.
+func (c *Client) process(ctx context.Context, key string, e Event, silence Silence) {
+	now := time.Now()
+
+	if e.Tags["kind"] == "Namespace" {
+		for _, summary := range c.silences.setNamespace(ctx, e.Name, silence, now) {
+			c.enqueue(ctx, summary)
+		}
+	}
+
+	transition := c.transitions.observe(key, e)
+	if e, ok := c.silences.observe(ctx, key, e, silence, transition, now); ok {
+		c.enqueue(ctx, newPendingEvent(key, e))
+	}
+}
+
+// enqueue queues the given event for delivery to all sinks.
+func (c *Client) enqueue(ctx context.Context, e pendingEvent) {
+	for _, d := range c.deliveries {
+		d.enqueue(ctx, e)
+	}
+}
+
+// marshal marshals the given event and resolves its URL against the configured Kubernetes web URL.
+func (c *Client) marshal(event Marshaler) (Event, error) {
+	e, err := event.MarshalEvent()
+	if err != nil {
+		return Event{}, errors.Wrapf(err, "cannot marshal notifications event of type: %T", event)
+	}
+
+	e.URL = c.webUrl.ResolveReference(e.URL)
+	if c.cluster != "" {
+		if e.Tags == nil {
+			e.Tags = make(map[string]string)
+		}
+
+		e.Tags["cluster"] = c.cluster
+	}
+
+	return e, nil
+}
+
+// eventKey returns the key of the object the given event is about, i.e. its UUID.
+func eventKey(e Event) string {
+	if key := e.Tags["uuid"]; key != "" {
+		return key
+	}
+
+	return e.Name
 }
@@ -4,14 +4,31 @@ import (
 	"github.com/pkg/errors"
 	"net/url"
 	"regexp"
+	"time"
 )
 
 type Config struct {
-	// If URL is the empty string, notifications are disabled.
+	// Url is the URL of Icinga Notifications. If empty, no events are sent to Icinga Notifications.
 	Url              string `yaml:"url"`
 	Username         string `yaml:"username"`
 	Password         string `yaml:"password"`
 	KubernetesWebUrl string `yaml:"kubernetes_web_url" default:"http://localhost/icingaweb2/kubernetes"`
+	// QueueSize is the maximum number of events pending delivery. Once exceeded, the oldest events are dropped.
+	QueueSize int `yaml:"queue_size" default:"1000"`
+	// RenotifyInterval is the interval after which an event is sent again for an object whose problem persists.
+	// If zero, events are only sent on state transitions.
+	RenotifyInterval time.Duration `yaml:"renotify_interval"`
+	// Outbox enables persisting events pending delivery in the database, so that they survive restarts.
+	Outbox bool `yaml:"outbox"`
+	// Alertmanager configures sending events as alerts to Alertmanager.
+	Alertmanager AlertmanagerConfig `yaml:"alertmanager"`
+	// Webhook configures sending events to an arbitrary HTTP endpoint.
+	Webhook WebhookConfig `yaml:"webhook"`
+}
+
+// Enabled returns whether events are sent to any receiver.
+func (c *Config) Enabled() bool {
+	return c.Url != "" || c.Alertmanager.Url != "" || c.Webhook.Url != ""
 }
 
 // Validate checks constraints in the supplied configuration and returns an error if they are violated.
@@ -38,5 +55,80 @@ func (c *Config) Validate() error {
 		return errors.Wrap(err, "'kubernetes_web_url' invalid")
 	}
 
+	if c.RenotifyInterval < 0 {
+		return errors.New("'renotify_interval' must not be negative")
+	}
+
+	if c.QueueSize < 1 {
+		return errors.New("'queue_size' must be at least 1")
+	}
+
+	if err := c.Alertmanager.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Webhook.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AlertmanagerConfig defines the connection to Alertmanager.
+type AlertmanagerConfig struct {
+	// Url is the URL of Alertmanager. If empty, no alerts are sent to Alertmanager.
+	Url      string `yaml:"url"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	// ResolveTimeout is the duration after which alerts of problems are resolved unless they are sent again.
+	ResolveTimeout time.Duration `yaml:"resolve_timeout" default:"24h"`
+}
+
+// Validate checks constraints in the supplied Alertmanager configuration and returns an error if they are violated.
+func (c *AlertmanagerConfig) Validate() error {
+	if c.Url == "" {
+		return nil
+	}
+
+	if _, err := url.Parse(c.Url); err != nil {
+		return errors.Wrap(err, "alertmanager 'url' invalid")
+	}
+
+	if c.Password != "" && c.Username == "" {
+		return errors.New("alertmanager 'password' requires 'username'")
+	}
+
+	if c.ResolveTimeout <= 0 {
+		return errors.New("alertmanager 'resolve_timeout' must be greater than zero")
+	}
+
+	return nil
+}
+
+// WebhookConfig defines an arbitrary HTTP endpoint to which events are posted.
+type WebhookConfig struct {
+	// Url is the URL to post events to. If empty, no events are sent to a webhook.
+	Url string `yaml:"url"`
+	// Template is a Go text/template that renders the request body from an event.
+	// If empty, events are rendered as JSON objects.
+	Template    string            `yaml:"template"`
+	ContentType string            `yaml:"content_type" default:"application/json"`
+	Headers     map[string]string `yaml:"headers"`
+}
+
+// Validate checks constraints in the supplied webhook configuration and returns an error if they are violated.
+func (c *WebhookConfig) Validate() error {
+	if c.Url == "" {
+		return nil
+	}
+
+	if _, err := url.Parse(c.Url); err != nil {
+		return errors.Wrap(err, "webhook 'url' invalid")
+	}
+
+	if _, err := parseWebhookTemplate(c.Template); err != nil {
+		return errors.Wrap(err, "webhook 'template' invalid")
+	}
+
 	return nil
 }
@@ -0,0 +1,123 @@
+package notifications
+
+import (
+	"context"
+	"github.com/icinga/icinga-go-library/backoff"
+	"github.com/icinga/icinga-go-library/retry"
+	"github.com/icinga/icinga-kubernetes/pkg/telemetry"
+	"k8s.io/klog/v2"
+	"time"
+)
+
+// delivery queues the events of a single sink and sends them one after the other,
+// so that a slow or unavailable sink does not hold up the others.
+type delivery struct {
+	sink   Sink
+	queue  *queue
+	outbox *Outbox
+}
+
+func newDelivery(sink Sink, queueSize int, outbox *Outbox) *delivery {
+	return &delivery{
+		sink:   sink,
+		queue:  newQueue(queueSize),
+		outbox: outbox,
+	}
+}
+
+// run sends the queued events to the sink until ctx is done.
+// Events that cannot be sent due to temporary errors are retried with exponential backoff.
+// If an outbox is configured, its events of the sink are queued first.
+func (d *delivery) run(ctx context.Context) error {
+	name := d.sink.Name()
+
+	if d.outbox != nil {
+		events, err := d.outbox.load(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		if len(events) > 0 {
+			klog.Infof("Delivering %d pending notification events from the outbox to %s", len(events), name)
+		}
+
+		for _, e := range events {
+			d.enqueue(ctx, e)
+		}
+	}
+
+	for {
+		e, err := d.queue.pop(ctx)
+		if err != nil {
+			return err
+		}
+		telemetry.NotificationsPending.WithLabelValues(name).Dec()
+
+		err = retry.WithBackoff(
+			ctx,
+			func(ctx context.Context) error {
+				return d.sink.Send(ctx, e.event)
+			},
+			retryable,
+			backoff.NewExponentialWithJitter(time.Second, time.Minute),
+			retry.Settings{
+				OnRetryableError: func(_ time.Duration, attempt uint64, err, lastErr error) {
+					telemetry.NotificationsRetries.WithLabelValues(name).Inc()
+
+					if lastErr == nil || err.Error() != lastErr.Error() {
+						klog.Warningf("Cannot send notifications event to %s, retrying: %v", name, err)
+					}
+				},
+				OnSuccess: func(elapsed time.Duration, attempt uint64, lastErr error) {
+					if attempt > 1 {
+						klog.Infof("Notifications event sent to %s after %d attempts in %s", name, attempt, elapsed)
+					}
+				},
+			},
+		)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			klog.Error(err)
+			telemetry.NotificationsFailures.WithLabelValues(name).Inc()
+		} else {
+			telemetry.NotificationsSent.WithLabelValues(name).Inc()
+		}
+
+		if d.outbox != nil && !d.queue.has(e.key) {
+			if err := d.outbox.delete(ctx, name, e); err != nil {
+				klog.Error(err)
+			}
+		}
+	}
+}
+
+// enqueue queues the given event and persists it in the outbox, if configured.
+func (d *delivery) enqueue(ctx context.Context, e pendingEvent) {
+	name := d.sink.Name()
+
+	if d.outbox != nil {
+		if err := d.outbox.save(ctx, name, e); err != nil {
+			klog.Error(err)
+		}
+	}
+
+	added, dropped := d.queue.push(e)
+	if added {
+		telemetry.NotificationsPending.WithLabelValues(name).Inc()
+	}
+
+	if dropped != nil {
+		klog.Warningf("Notifications queue of %s is full, dropping oldest event of object %s", name, dropped.key)
+		telemetry.NotificationsDropped.WithLabelValues(name).Inc()
+		telemetry.NotificationsPending.WithLabelValues(name).Dec()
+
+		if d.outbox != nil {
+			if err := d.outbox.delete(ctx, name, *dropped); err != nil {
+				klog.Error(err)
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package notifications
 
 import (
 	"encoding/json"
+	"github.com/pkg/errors"
 	"net/url"
 )
 
@@ -14,15 +15,18 @@ type Event struct {
 	ExtraTags map[string]string
 }
 
+// eventJSON is the JSON representation of an Event, as expected by Icinga Notifications.
+type eventJSON struct {
+	Name      string            `json:"name"`
+	Severity  string            `json:"severity"`
+	Message   string            `json:"message"`
+	URL       string            `json:"json"`
+	Tags      map[string]string `json:"tags"`
+	ExtraTags map[string]string `json:"extra_tags"`
+}
+
 func (e Event) MarshalJSON() ([]byte, error) {
-	return json.Marshal(struct {
-		Name      string            `json:"name"`
-		Severity  string            `json:"severity"`
-		Message   string            `json:"message"`
-		URL       string            `json:"json"`
-		Tags      map[string]string `json:"tags"`
-		ExtraTags map[string]string `json:"extra_tags"`
-	}{
+	return json.Marshal(eventJSON{
 		Name:      e.Name,
 		Severity:  e.Severity,
 		Message:   e.Message,
@@ -31,3 +35,27 @@ func (e Event) MarshalJSON() ([]byte, error) {
 		ExtraTags: e.ExtraTags,
 	})
 }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var v eventJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	u, err := url.Parse(v.URL)
+	if err != nil {
+		return errors.Wrap(err, "cannot parse event URL")
+	}
+
+	*e = Event{
+		Name:      v.Name,
+		Severity:  v.Severity,
+		Message:   v.Message,
+		URL:       u,
+		Tags:      v.Tags,
+		ExtraTags: v.ExtraTags,
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/icinga/icinga-kubernetes/pkg/com"
+	"github.com/pkg/errors"
+	"net/http"
+	"net/url"
+)
+
+// icingaNotificationsSink sends events to the process-event API of Icinga Notifications.
+type icingaNotificationsSink struct {
+	httpSender
+	processEventUrl string
+}
+
+func newIcingaNotificationsSink(userAgent string, config *Config) (*icingaNotificationsSink, error) {
+	baseUrl, err := url.Parse(config.Url)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse url")
+	}
+
+	return &icingaNotificationsSink{
+		httpSender: httpSender{
+			client: http.Client{
+				Transport: &com.BasicAuthTransport{
+					RoundTripper: http.DefaultTransport,
+					Username:     config.Username,
+					Password:     config.Password,
+				},
+			},
+			userAgent: userAgent,
+		},
+		processEventUrl: baseUrl.ResolveReference(&url.URL{Path: "/process-event"}).String(),
+	}, nil
+}
+
+// Name implements the Sink interface.
+func (s *icingaNotificationsSink) Name() string {
+	return "icinga_notifications"
+}
+
+// Send implements the Sink interface.
+// Icinga Notifications responds with 406 Not Acceptable to events that do not change the state of an object,
+// which is therefore not considered an error.
+func (s *icingaNotificationsSink) Send(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrapf(err, "cannot marshal notifications event data of type: %T", e)
+	}
+
+	return s.post(ctx, s.processEventUrl, "application/json", body, http.StatusOK, http.StatusNotAcceptable)
+}
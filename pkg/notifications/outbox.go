@@ -0,0 +1,128 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/icinga/icinga-go-library/types"
+	"github.com/icinga/icinga-kubernetes/pkg/database"
+	"github.com/pkg/errors"
+)
+
+// Outbox persists pending events in the database, so that they are still delivered after a restart.
+type Outbox struct {
+	db          *database.Database
+	clusterUuid types.UUID
+}
+
+// NewOutbox returns a new Outbox that persists the pending events of the given cluster.
+func NewOutbox(db *database.Database, clusterUuid types.UUID) *Outbox {
+	return &Outbox{
+		db:          db,
+		clusterUuid: clusterUuid,
+	}
+}
+
+// outboxEvent is the database representation of a pending event of a sink.
+type outboxEvent struct {
+	Uuid        types.UUID
+	Sink        string
+	ClusterUuid types.UUID
+	Event       string
+	Created     types.UnixMilli
+}
+
+// TableName implements the database.TableNamer interface.
+func (outboxEvent) TableName() string {
+	return "notification_outbox"
+}
+
+// load returns the pending events of the given sink of the cluster, oldest first.
+func (o *Outbox) load(ctx context.Context, sink string) ([]pendingEvent, error) {
+	var rows []outboxEvent
+	if err := o.db.SelectContext(
+		ctx,
+		&rows,
+		o.db.Rebind(fmt.Sprintf(
+			"%s WHERE %s = ? AND %s = ? ORDER BY %s",
+			o.db.BuildSelectStmt(outboxEvent{}, outboxEvent{}),
+			o.db.QuoteIdentifier("cluster_uuid"),
+			o.db.QuoteIdentifier("sink"),
+			o.db.QuoteIdentifier("created"),
+		)),
+		o.clusterUuid,
+		sink,
+	); err != nil {
+		return nil, errors.Wrap(err, "cannot select pending notification events")
+	}
+
+	events := make([]pendingEvent, 0, len(rows))
+	for _, row := range rows {
+		var e Event
+		if err := json.Unmarshal([]byte(row.Event), &e); err != nil {
+			return nil, errors.Wrap(err, "cannot unmarshal pending notification event")
+		}
+
+		events = append(events, pendingEvent{
+			key:     row.Uuid.String(),
+			event:   e,
+			created: row.Created.Time(),
+		})
+	}
+
+	return events, nil
+}
+
+// save persists the given event of the given sink, replacing any pending event of the same object.
+func (o *Outbox) save(ctx context.Context, sink string, e pendingEvent) error {
+	id, err := uuid.Parse(e.key)
+	if err != nil {
+		return errors.Wrapf(err, "cannot parse object UUID %q", e.key)
+	}
+
+	event, err := json.Marshal(e.event)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal pending notification event")
+	}
+
+	stmt, _ := o.db.BuildUpsertStmt(outboxEvent{})
+	if _, err := o.db.NamedExecContext(ctx, stmt, outboxEvent{
+		Uuid:        types.UUID{UUID: id},
+		Sink:        sink,
+		ClusterUuid: o.clusterUuid,
+		Event:       string(event),
+		Created:     types.UnixMilli(e.created),
+	}); err != nil {
+		return errors.Wrap(err, "cannot persist pending notification event")
+	}
+
+	return nil
+}
+
+// delete removes the given event of the given sink
+// unless it has already been replaced by a newer event of the same object.
+func (o *Outbox) delete(ctx context.Context, sink string, e pendingEvent) error {
+	id, err := uuid.Parse(e.key)
+	if err != nil {
+		return errors.Wrapf(err, "cannot parse object UUID %q", e.key)
+	}
+
+	if _, err := o.db.ExecContext(
+		ctx,
+		o.db.Rebind(fmt.Sprintf(
+			"DELETE FROM %s WHERE %s = ? AND %s = ? AND %s = ?",
+			o.db.QuoteIdentifier(outboxEvent{}.TableName()),
+			o.db.QuoteIdentifier("uuid"),
+			o.db.QuoteIdentifier("sink"),
+			o.db.QuoteIdentifier("created"),
+		)),
+		types.UUID{UUID: id},
+		sink,
+		e.created.UnixMilli(),
+	); err != nil {
+		return errors.Wrap(err, "cannot delete pending notification event")
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package notifications
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pendingEvent is an event that is pending delivery.
+type pendingEvent struct {
+	// key identifies the object the event is about, i.e. its UUID.
+	key     string
+	event   Event
+	created time.Time
+}
+
+// newPendingEvent returns a pendingEvent for the given key and event created now.
+// The creation time is truncated to milliseconds, as it is persisted as such.
+func newPendingEvent(key string, event Event) pendingEvent {
+	return pendingEvent{
+		key:     key,
+		event:   event,
+		created: time.UnixMilli(time.Now().UnixMilli()),
+	}
+}
+
+// queue is a bounded FIFO of pending events that holds at most one event per key.
+// Pushing an event for a key that is already queued replaces the queued event in place,
+// so that only the latest state of an object is delivered.
+type queue struct {
+	size   int
+	mu     sync.Mutex
+	keys   []string
+	events map[string]pendingEvent
+	signal chan struct{}
+}
+
+func newQueue(size int) *queue {
+	return &queue{
+		size:   size,
+		events: make(map[string]pendingEvent),
+		signal: make(chan struct{}, 1),
+	}
+}
+
+// push queues the given event and returns whether it has been added rather than replaced a queued event.
+// If the queue is full, the oldest event is removed and returned.
+func (q *queue) push(e pendingEvent) (added bool, dropped *pendingEvent) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.events[e.key]; !ok {
+		added = true
+
+		if len(q.keys) >= q.size {
+			oldest := q.events[q.keys[0]]
+			delete(q.events, oldest.key)
+			q.keys = q.keys[1:]
+			dropped = &oldest
+		}
+
+		q.keys = append(q.keys, e.key)
+	}
+
+	q.events[e.key] = e
+
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+
+	return added, dropped
+}
+
+// pop blocks until an event is queued or ctx is done and removes and returns the oldest event.
+func (q *queue) pop(ctx context.Context) (pendingEvent, error) {
+	for {
+		q.mu.Lock()
+		if len(q.keys) > 0 {
+			e := q.events[q.keys[0]]
+			delete(q.events, e.key)
+			q.keys = q.keys[1:]
+			q.mu.Unlock()
+
+			return e, nil
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-q.signal:
+		case <-ctx.Done():
+			return pendingEvent{}, ctx.Err()
+		}
+	}
+}
+
+// has returns whether an event for the given key is queued.
+func (q *queue) has(key string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	_, ok := q.events[key]
+
+	return ok
+}
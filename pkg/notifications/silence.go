@@ -0,0 +1,81 @@
+package notifications
+
+import (
+	"k8s.io/klog/v2"
+	"strconv"
+	"time"
+)
+
+const (
+	// SilenceUntilAnnotation suppresses the events of an object until the RFC 3339 time it is set to.
+	SilenceUntilAnnotation = "icinga.com/silence-until"
+
+	// MaintenanceAnnotation downgrades the problems of an object to informational events while it is set to true.
+	MaintenanceAnnotation = "icinga.com/maintenance"
+)
+
+// Silence suppresses or downgrades the events of an object, e.g. during planned node drains and deployments.
+// Objects in a namespace inherit the silence of the namespace unless they are silenced themselves.
+type Silence struct {
+	// Until is the time until which events are suppressed.
+	Until time.Time
+
+	// Maintenance downgrades problems to informational events as long as it is set.
+	Maintenance bool
+}
+
+// ParseSilence returns the silence defined by the given annotations of an object.
+// Invalid annotation values are logged and ignored.
+func ParseSilence(annotations map[string]string) Silence {
+	var s Silence
+
+	if v, ok := annotations[SilenceUntilAnnotation]; ok {
+		until, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			klog.Warningf("Ignoring invalid %s annotation %q: %v", SilenceUntilAnnotation, v, err)
+		} else {
+			s.Until = until
+		}
+	}
+
+	if v, ok := annotations[MaintenanceAnnotation]; ok {
+		maintenance, err := strconv.ParseBool(v)
+		if err != nil {
+			klog.Warningf("Ignoring invalid %s annotation %q: %v", MaintenanceAnnotation, v, err)
+		} else {
+			s.Maintenance = maintenance
+		}
+	}
+
+	return s
+}
+
+// Silencer is implemented by objects whose events can be silenced.
+type Silencer interface {
+	Silence() Silence
+}
+
+// suppresses returns whether events are suppressed at the given time.
+func (s Silence) suppresses(now time.Time) bool {
+	return now.Before(s.Until)
+}
+
+// active returns whether events are suppressed or downgraded at the given time.
+func (s Silence) active(now time.Time) bool {
+	return s.Maintenance || s.suppresses(now)
+}
+
+// equal returns whether the given silence is the same as s.
+func (s Silence) equal(other Silence) bool {
+	return s.Until.Equal(other.Until) && s.Maintenance == other.Maintenance
+}
+
+// downgrade returns the given event with its problem downgraded to an informational event.
+func downgrade(e Event) Event {
+	if e.Severity != "ok" {
+		e.Severity = "info"
+		e.Message = "In maintenance: " + e.Message
+	}
+
+	return e
+}
@@ -0,0 +1,130 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/icinga/icinga-go-library/types"
+	"github.com/icinga/icinga-kubernetes/pkg/database"
+	"github.com/pkg/errors"
+)
+
+// SilenceStore persists the silenced objects in the database,
+// so that their summaries are still sent after a restart.
+type SilenceStore struct {
+	db          *database.Database
+	clusterUuid types.UUID
+}
+
+// NewSilenceStore returns a new SilenceStore that persists the silenced objects of the given cluster.
+func NewSilenceStore(db *database.Database, clusterUuid types.UUID) *SilenceStore {
+	return &SilenceStore{
+		db:          db,
+		clusterUuid: clusterUuid,
+	}
+}
+
+// silenceRow is the database representation of a silenced object.
+type silenceRow struct {
+	Uuid          types.UUID
+	ClusterUuid   types.UUID
+	SilencedUntil types.UnixMilli
+	Maintenance   types.Bool
+	Namespace     sql.NullString
+	Since         types.UnixMilli
+	Changes       int
+	Event         string
+}
+
+// TableName implements the database.TableNamer interface.
+func (silenceRow) TableName() string {
+	return "notification_silence"
+}
+
+// load returns the silenced objects of the cluster by their keys.
+func (s *SilenceStore) load(ctx context.Context) (map[string]*silenced, error) {
+	var rows []silenceRow
+	if err := s.db.SelectContext(
+		ctx,
+		&rows,
+		s.db.Rebind(fmt.Sprintf(
+			"%s WHERE %s = ?",
+			s.db.BuildSelectStmt(silenceRow{}, silenceRow{}),
+			s.db.QuoteIdentifier("cluster_uuid"),
+		)),
+		s.clusterUuid,
+	); err != nil {
+		return nil, errors.Wrap(err, "cannot select silenced objects")
+	}
+
+	states := make(map[string]*silenced, len(rows))
+	for _, row := range rows {
+		var e Event
+		if err := json.Unmarshal([]byte(row.Event), &e); err != nil {
+			return nil, errors.Wrap(err, "cannot unmarshal event of silenced object")
+		}
+
+		states[row.Uuid.String()] = &silenced{
+			silence:   Silence{Until: row.SilencedUntil.Time(), Maintenance: row.Maintenance.Bool},
+			namespace: row.Namespace.String,
+			since:     row.Since.Time(),
+			changes:   row.Changes,
+			event:     e,
+		}
+	}
+
+	return states, nil
+}
+
+// save persists the given state of the object with the given key.
+func (s *SilenceStore) save(ctx context.Context, key string, state *silenced) error {
+	id, err := uuid.Parse(key)
+	if err != nil {
+		return errors.Wrapf(err, "cannot parse object UUID %q", key)
+	}
+
+	event, err := json.Marshal(state.event)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal event of silenced object")
+	}
+
+	stmt, _ := s.db.BuildUpsertStmt(silenceRow{})
+	if _, err := s.db.NamedExecContext(ctx, stmt, silenceRow{
+		Uuid:          types.UUID{UUID: id},
+		ClusterUuid:   s.clusterUuid,
+		SilencedUntil: types.UnixMilli(state.silence.Until),
+		Maintenance:   types.Bool{Bool: state.silence.Maintenance, Valid: true},
+		Namespace:     sql.NullString{String: state.namespace, Valid: state.namespace != ""},
+		Since:         types.UnixMilli(state.since),
+		Changes:       state.changes,
+		Event:         string(event),
+	}); err != nil {
+		return errors.Wrap(err, "cannot persist silenced object")
+	}
+
+	return nil
+}
+
+// delete removes the object with the given key.
+func (s *SilenceStore) delete(ctx context.Context, key string) error {
+	id, err := uuid.Parse(key)
+	if err != nil {
+		return errors.Wrapf(err, "cannot parse object UUID %q", key)
+	}
+
+	if _, err := s.db.ExecContext(
+		ctx,
+		s.db.Rebind(fmt.Sprintf(
+			"DELETE FROM %s WHERE %s = ?",
+			s.db.QuoteIdentifier(silenceRow{}.TableName()),
+			s.db.QuoteIdentifier("uuid"),
+		)),
+		types.UUID{UUID: id},
+	); err != nil {
+		return errors.Wrap(err, "cannot delete silenced object")
+	}
+
+	return nil
+}
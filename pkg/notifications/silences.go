@@ -0,0 +1,273 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"k8s.io/klog/v2"
+	"sync"
+	"time"
+)
+
+// silenced is the state of an object whose events are silenced.
+type silenced struct {
+	silence Silence
+	// namespace is the name of the namespace the silence is inherited from, if any.
+	namespace string
+	since     time.Time
+	// changes is the number of state transitions of the object while silenced.
+	changes int
+	// event is the latest event of the object, from which the summary is sent once the silence ends.
+	event Event
+}
+
+// summary returns the event that is sent once the silence ends, i.e. the latest event of the object
+// along with the number of state transitions while silenced.
+func (s *silenced) summary(now time.Time) Event {
+	e := s.event
+	e.Message = fmt.Sprintf(
+		"Silence ended after %s with %d state changes in the meantime. %s",
+		now.Sub(s.since).Round(time.Second), s.changes, e.Message)
+
+	return e
+}
+
+// silences tracks the objects whose events are silenced, so that a summary can be sent once their silence ends.
+type silences struct {
+	store      *SilenceStore
+	mu         sync.Mutex
+	namespaces map[string]Silence
+	silenced   map[string]*silenced
+}
+
+func newSilences(store *SilenceStore) *silences {
+	return &silences{
+		store:      store,
+		namespaces: make(map[string]Silence),
+		silenced:   make(map[string]*silenced),
+	}
+}
+
+// restore loads the silenced objects from the store, if any.
+func (s *silences) restore(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+
+	restored, err := s.store.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, state := range restored {
+		s.silenced[key] = state
+
+		if state.event.Tags["kind"] == "Namespace" && state.namespace == "" {
+			s.namespaces[state.event.Name] = state.silence
+		}
+	}
+
+	return nil
+}
+
+// observe records the given event of the object with the given key and its own silence.
+// It returns the event to send, if any, which is the given event on state transitions,
+// downgraded during maintenance, or the summary if the silence of the object has ended.
+func (s *silences) observe(
+	ctx context.Context, key string, e Event, own Silence, transition bool, now time.Time,
+) (Event, bool) {
+	s.mu.Lock()
+
+	silence, namespace := s.effective(own, e.Tags["namespace"], now)
+	state, ok := s.silenced[key]
+
+	if !silence.active(now) {
+		if !ok {
+			s.mu.Unlock()
+
+			return e, transition
+		}
+
+		delete(s.silenced, key)
+		s.mu.Unlock()
+
+		s.delete(ctx, key)
+
+		if state.changes > 0 {
+			state.event = e
+
+			return state.summary(now), true
+		}
+
+		return e, transition
+	}
+
+	changed := !ok || transition || !state.silence.equal(silence) || state.namespace != namespace
+	if !ok {
+		state = &silenced{since: now}
+		s.silenced[key] = state
+	}
+
+	state.silence = silence
+	state.namespace = namespace
+	state.event = e
+	if transition {
+		state.changes++
+	}
+
+	snapshot := *state
+	s.mu.Unlock()
+
+	if changed {
+		s.save(ctx, key, &snapshot)
+	}
+
+	if transition && !silence.suppresses(now) {
+		return downgrade(e), true
+	}
+
+	return Event{}, false
+}
+
+// setNamespace records the own silence of the namespace with the given name, which its objects inherit,
+// and returns the summaries of the objects whose inherited silence has ended.
+func (s *silences) setNamespace(ctx context.Context, name string, silence Silence, now time.Time) []pendingEvent {
+	s.mu.Lock()
+
+	active := silence.active(now)
+	if active {
+		s.namespaces[name] = silence
+	} else {
+		delete(s.namespaces, name)
+	}
+
+	var updated []*silenced
+	var updatedKeys, ended []string
+	var summaries []pendingEvent
+	for key, state := range s.silenced {
+		if state.namespace != name {
+			continue
+		}
+
+		if active {
+			if !state.silence.equal(silence) {
+				state.silence = silence
+				snapshot := *state
+				updated = append(updated, &snapshot)
+				updatedKeys = append(updatedKeys, key)
+			}
+
+			continue
+		}
+
+		delete(s.silenced, key)
+		ended = append(ended, key)
+
+		if state.changes > 0 {
+			summaries = append(summaries, newPendingEvent(key, state.summary(now)))
+		}
+	}
+
+	s.mu.Unlock()
+
+	for i, key := range updatedKeys {
+		s.save(ctx, key, updated[i])
+	}
+
+	for _, key := range ended {
+		s.delete(ctx, key)
+	}
+
+	return summaries
+}
+
+// expire ends the silences that have expired by the given time and returns the summaries of their objects.
+func (s *silences) expire(ctx context.Context, now time.Time) []pendingEvent {
+	s.mu.Lock()
+
+	for name, silence := range s.namespaces {
+		if !silence.active(now) {
+			delete(s.namespaces, name)
+		}
+	}
+
+	var ended []string
+	var summaries []pendingEvent
+	for key, state := range s.silenced {
+		if state.silence.active(now) {
+			continue
+		}
+
+		delete(s.silenced, key)
+		ended = append(ended, key)
+
+		if state.changes > 0 {
+			summaries = append(summaries, newPendingEvent(key, state.summary(now)))
+		}
+	}
+
+	s.mu.Unlock()
+
+	for _, key := range ended {
+		s.delete(ctx, key)
+	}
+
+	return summaries
+}
+
+// active returns whether the events of the object with the given key are silenced at the given time.
+func (s *silences) active(key string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.silenced[key]
+
+	return ok && state.silence.active(now)
+}
+
+// forget removes the object with the given key, e.g. because it has been deleted.
+func (s *silences) forget(ctx context.Context, key string) {
+	s.mu.Lock()
+	_, ok := s.silenced[key]
+	delete(s.silenced, key)
+	s.mu.Unlock()
+
+	if ok {
+		s.delete(ctx, key)
+	}
+}
+
+// effective returns the silence of an object in the given namespace with the given own silence,
+// which is inherited from the namespace unless the object is silenced itself,
+// and the name of the namespace it is inherited from, if any.
+func (s *silences) effective(own Silence, namespace string, now time.Time) (Silence, string) {
+	if own.active(now) || namespace == "" {
+		return own, ""
+	}
+
+	if silence, ok := s.namespaces[namespace]; ok && silence.active(now) {
+		return silence, namespace
+	}
+
+	return own, ""
+}
+
+// save persists the given state of the object with the given key in the store, if any.
+func (s *silences) save(ctx context.Context, key string, state *silenced) {
+	if s.store != nil {
+		if err := s.store.save(ctx, key, state); err != nil {
+			klog.Error(err)
+		}
+	}
+}
+
+// delete removes the state of the object with the given key from the store, if any.
+func (s *silences) delete(ctx context.Context, key string) {
+	if s.store != nil {
+		if err := s.store.delete(ctx, key); err != nil {
+			klog.Error(err)
+		}
+	}
+}
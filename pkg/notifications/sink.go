@@ -0,0 +1,82 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/icinga/icinga-go-library/retry"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"slices"
+)
+
+// Sink delivers notification events to a receiver, e.g. Icinga Notifications or Alertmanager.
+type Sink interface {
+	// Name returns the name of the sink, which distinguishes its pending events, e.g. in the outbox and metrics.
+	Name() string
+
+	// Send delivers the given event. Errors after which sending can be retried must satisfy retryable.
+	Send(ctx context.Context, e Event) error
+}
+
+// httpSender posts request bodies to HTTP receivers.
+type httpSender struct {
+	client    http.Client
+	userAgent string
+	headers   map[string]string
+}
+
+// post sends the given body to the given URL with the given content type.
+// It returns a *statusError if the response status code is not one of the accepted ones.
+func (s *httpSender) post(ctx context.Context, url, contentType string, body []byte, accepted ...int) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "cannot create new notifications http request")
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", s.userAgent)
+	for name, value := range s.headers {
+		req.Header.Set(name, value)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "cannot send notifications event")
+	}
+
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if !slices.Contains(accepted, res.StatusCode) {
+		msg, _ := io.ReadAll(res.Body)
+
+		return &statusError{code: res.StatusCode, msg: string(msg)}
+	}
+
+	return nil
+}
+
+// statusError is returned by httpSender.post if the receiver responds with an unexpected HTTP status code.
+type statusError struct {
+	code int
+	msg  string
+}
+
+// Error implements the error interface.
+func (e *statusError) Error() string {
+	return fmt.Sprintf("received unexpected http status code: %d: %s", e.code, e.msg)
+}
+
+// retryable returns whether sending an event can be retried after the given error,
+// i.e. for server errors, rate limiting and temporary network errors.
+func retryable(err error) bool {
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.code >= http.StatusInternalServerError || statusErr.code == http.StatusTooManyRequests
+	}
+
+	return retry.Retryable(err)
+}
@@ -0,0 +1,100 @@
+package notifications
+
+import (
+	"sync"
+	"time"
+)
+
+// transitions tracks the severity that has last been notified per object, so that events are only sent
+// on state transitions and, if renotifyInterval is greater than zero, periodically for persistent problems.
+type transitions struct {
+	renotifyInterval time.Duration
+	mu               sync.Mutex
+	notified         map[string]notified
+}
+
+// notified is the severity of an object that has last been notified, when,
+// and the latest event, if any, to send it again.
+type notified struct {
+	severity string
+	at       time.Time
+	event    *Event
+}
+
+func newTransitions(renotifyInterval time.Duration) *transitions {
+	return &transitions{
+		renotifyInterval: renotifyInterval,
+		notified:         make(map[string]notified),
+	}
+}
+
+// observe records the current severity of the object identified by key and returns whether it is to be notified,
+// i.e. if it differs from the severity that has last been notified, or if the renotify interval of a problem
+// has elapsed. Objects that have not been seen before are only notified if they are not ok.
+// The given event is kept for renotification.
+func (t *transitions) observe(key string, e Event) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	last, ok := t.notified[key]
+
+	switch {
+	case !ok:
+		t.notified[key] = notified{severity: e.Severity, at: now, event: &e}
+
+		return e.Severity != "ok"
+	case last.severity != e.Severity:
+	case t.renotify(last, now):
+	default:
+		last.event = &e
+		t.notified[key] = last
+
+		return false
+	}
+
+	t.notified[key] = notified{severity: e.Severity, at: now, event: &e}
+
+	return true
+}
+
+// due returns the events of problems whose renotify interval has elapsed and records them as notified now.
+func (t *transitions) due() []pendingEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	var events []pendingEvent
+	for key, last := range t.notified {
+		if last.event != nil && t.renotify(last, now) {
+			last.at = now
+			t.notified[key] = last
+
+			events = append(events, newPendingEvent(key, *last.event))
+		}
+	}
+
+	return events
+}
+
+// renotify returns whether the given notified problem is to be notified again at now.
+func (t *transitions) renotify(last notified, now time.Time) bool {
+	return last.severity != "ok" && t.renotifyInterval > 0 && now.Sub(last.at) >= t.renotifyInterval
+}
+
+// remember records the given severity of the object identified by key as notified now,
+// e.g. to warm-start after a restart.
+func (t *transitions) remember(key, severity string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.notified[key] = notified{severity: severity, at: time.Now()}
+}
+
+// forget removes the object identified by key, e.g. after it has been deleted.
+func (t *transitions) forget(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.notified, key)
+}
@@ -0,0 +1,102 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"net/http"
+	"text/template"
+)
+
+// defaultWebhookTemplate renders events as JSON objects if no template is configured.
+const defaultWebhookTemplate = `{"name":{{json .Name}},"severity":{{json .Severity}},"message":{{json .Message}},` +
+	`"url":{{json .URL}},"tags":{{json .Tags}}}`
+
+// webhookFuncs are the functions available in webhook templates.
+var webhookFuncs = template.FuncMap{
+	// json renders the given value as JSON, e.g. to safely embed strings.
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+
+		return string(b), err
+	},
+}
+
+// webhookSink posts events rendered with a template to an arbitrary HTTP endpoint.
+type webhookSink struct {
+	httpSender
+	url         string
+	contentType string
+	template    *template.Template
+}
+
+// webhookData is the data passed to webhook templates.
+type webhookData struct {
+	Name      string
+	Severity  string
+	Message   string
+	URL       string
+	Tags      map[string]string
+	ExtraTags map[string]string
+}
+
+func newWebhookSink(userAgent string, config *WebhookConfig) (*webhookSink, error) {
+	tmpl, err := parseWebhookTemplate(config.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookSink{
+		httpSender: httpSender{
+			userAgent: userAgent,
+			headers:   config.Headers,
+		},
+		url:         config.Url,
+		contentType: config.ContentType,
+		template:    tmpl,
+	}, nil
+}
+
+// parseWebhookTemplate parses the given template or the default template if it is empty.
+func parseWebhookTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = defaultWebhookTemplate
+	}
+
+	tmpl, err := template.New("webhook").Funcs(webhookFuncs).Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse webhook template")
+	}
+
+	return tmpl, nil
+}
+
+// Name implements the Sink interface.
+func (s *webhookSink) Name() string {
+	return "webhook"
+}
+
+// Send implements the Sink interface.
+// The common 2xx response status codes of webhooks are considered successful.
+func (s *webhookSink) Send(ctx context.Context, e Event) error {
+	data := webhookData{
+		Name:      e.Name,
+		Severity:  e.Severity,
+		Message:   e.Message,
+		Tags:      e.Tags,
+		ExtraTags: e.ExtraTags,
+	}
+	if e.URL != nil {
+		data.URL = e.URL.String()
+	}
+
+	var body bytes.Buffer
+	if err := s.template.Execute(&body, data); err != nil {
+		return errors.Wrap(err, "cannot render webhook template")
+	}
+
+	return s.post(
+		ctx, s.url, s.contentType, body.Bytes(),
+		http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent)
+}
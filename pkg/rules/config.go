@@ -0,0 +1,98 @@
+package rules
+
+import (
+	schemav1 "github.com/icinga/icinga-kubernetes/pkg/schema/v1"
+	"github.com/pkg/errors"
+	"slices"
+)
+
+// kinds are the Kubernetes kinds with an Icinga state, to which rules can apply.
+var kinds = []string{
+	"CronJob",
+	"DaemonSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"Ingress",
+	"Job",
+	"Namespace",
+	"Node",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"Pod",
+	"PodDisruptionBudget",
+	"ReplicaSet",
+	"Service",
+	"StatefulSet",
+}
+
+// Config defines custom rules that override or augment the Icinga states of Kubernetes objects.
+// The rules are evaluated in order, so that later rules see the state set by earlier ones.
+type Config []Rule
+
+// Rule sets the Icinga state of the objects of a Kubernetes kind for which its condition is true.
+type Rule struct {
+	// Name identifies the rule in logs.
+	Name string `yaml:"name"`
+	// Kind is the Kubernetes kind of the objects the rule applies to, e.g. Pod.
+	Kind string `yaml:"kind"`
+	// Condition is a CEL expression that evaluates to true if the rule applies to an object.
+	Condition string `yaml:"condition"`
+	// State is the Icinga state to set if the condition is true, i.e. ok, pending, unknown, warning or critical.
+	State string `yaml:"state"`
+	// Reason is a CEL expression that evaluates to the reason of the state.
+	// If empty, the reason names the rule.
+	Reason string `yaml:"reason"`
+	// Override sets the state even if it is better than the current one, e.g. to downgrade a critical state.
+	// Otherwise, the state is only set if it is worse than the current one.
+	Override bool `yaml:"override"`
+}
+
+// Validate checks constraints in the supplied configuration and returns an error if they are violated.
+// This includes compiling the CEL expressions of all rules.
+func (c Config) Validate() error {
+	env, err := newEnv()
+	if err != nil {
+		return err
+	}
+
+	names := make(map[string]struct{}, len(c))
+	for i := range c {
+		if err := c[i].validate(env); err != nil {
+			return errors.Wrapf(err, "invalid state rule %q", c[i].Name)
+		}
+
+		if _, ok := names[c[i].Name]; ok {
+			return errors.Errorf("state rule name %q must be unique", c[i].Name)
+		}
+
+		names[c[i].Name] = struct{}{}
+	}
+
+	return nil
+}
+
+func (r *Rule) validate(env *celEnv) error {
+	if r.Name == "" {
+		return errors.New("'name' missing")
+	}
+
+	if !slices.Contains(kinds, r.Kind) {
+		return errors.Errorf("'kind' must be one of %v", kinds)
+	}
+
+	if _, err := schemav1.ParseIcingaState(r.State); err != nil {
+		return errors.Wrap(err, "'state' invalid")
+	}
+
+	if _, err := env.compileCondition(r.Condition); err != nil {
+		return errors.Wrap(err, "'condition' invalid")
+	}
+
+	if r.Reason != "" {
+		if _, err := env.compileReason(r.Reason); err != nil {
+			return errors.Wrap(err, "'reason' invalid")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package rules
+
+import (
+	"fmt"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	schemav1 "github.com/icinga/icinga-kubernetes/pkg/schema/v1"
+	"github.com/pkg/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+	"time"
+)
+
+// celEnv is the CEL environment of rule expressions, which can access
+// the Kubernetes object as `object`, its current Icinga state and reason as `state` and `reason`,
+// and the current time as `now`.
+type celEnv struct {
+	*cel.Env
+}
+
+func newEnv() (*celEnv, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		cel.Variable("state", cel.StringType),
+		cel.Variable("reason", cel.StringType),
+		cel.Variable("now", cel.TimestampType),
+		ext.Strings(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create CEL environment")
+	}
+
+	return &celEnv{Env: env}, nil
+}
+
+// compileCondition compiles the given expression, which must evaluate to a bool.
+func (e *celEnv) compileCondition(expr string) (cel.Program, error) {
+	return e.compile(expr, cel.BoolType)
+}
+
+// compileReason compiles the given expression, which must evaluate to a string.
+func (e *celEnv) compileReason(expr string) (cel.Program, error) {
+	return e.compile(expr, cel.StringType)
+}
+
+func (e *celEnv) compile(expr string, outputType *cel.Type) (cel.Program, error) {
+	ast, issues := e.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+
+	if !ast.OutputType().IsExactType(outputType) && !ast.OutputType().IsExactType(cel.DynType) {
+		return nil, errors.Errorf("expression must evaluate to %s, not %s", outputType, ast.OutputType())
+	}
+
+	program, err := e.Program(ast)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return program, nil
+}
+
+// Rules are the compiled rules of a Config by Kubernetes kind.
+type Rules struct {
+	rules map[string][]*rule
+}
+
+// rule is a compiled Rule.
+type rule struct {
+	name      string
+	state     schemav1.IcingaState
+	override  bool
+	condition cel.Program
+	reason    cel.Program
+}
+
+// NewRules compiles the rules of the given configuration, which must be valid.
+func NewRules(config Config) (*Rules, error) {
+	env, err := newEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Rules{rules: make(map[string][]*rule)}
+	for _, c := range config {
+		state, err := schemav1.ParseIcingaState(c.State)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid state rule %q", c.Name)
+		}
+
+		condition, err := env.compileCondition(c.Condition)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot compile condition of state rule %q", c.Name)
+		}
+
+		var reason cel.Program
+		if c.Reason != "" {
+			reason, err = env.compileReason(c.Reason)
+			if err != nil {
+				return nil, errors.Wrapf(err, "cannot compile reason of state rule %q", c.Name)
+			}
+		}
+
+		r.rules[c.Kind] = append(r.rules[c.Kind], &rule{
+			name:      c.Name,
+			state:     state,
+			override:  c.Override,
+			condition: condition,
+			reason:    reason,
+		})
+	}
+
+	return r, nil
+}
+
+// Apply evaluates the rules of the kind of the given Kubernetes object and sets the Icinga state
+// of the given entity, which has been obtained from the object, accordingly.
+// Rules that cannot be evaluated, e.g. because they access fields the object does not have, are skipped,
+// and the first of their errors is returned after all rules have been evaluated.
+func (r *Rules) Apply(k8s kmetav1.Object, entity schemav1.IcingaStater) error {
+	if len(r.rules) == 0 {
+		return nil
+	}
+
+	object, ok := k8s.(kruntime.Object)
+	if !ok {
+		return errors.Errorf("cannot apply state rules to %T", k8s)
+	}
+
+	gvks, _, err := kscheme.Scheme.ObjectKinds(object)
+	if err != nil {
+		return errors.Wrapf(err, "cannot determine kind of %T", object)
+	}
+
+	rules := r.rules[gvks[0].Kind]
+	if len(rules) == 0 {
+		return nil
+	}
+
+	unstructured, err := kruntime.DefaultUnstructuredConverter.ToUnstructured(object)
+	if err != nil {
+		return errors.Wrapf(err, "cannot convert %T to unstructured", object)
+	}
+
+	state, reason := entity.GetIcingaState()
+	now := time.Now()
+
+	var firstErr error
+	for _, rule := range rules {
+		vars := map[string]any{
+			"object": unstructured,
+			"state":  state.String(),
+			"reason": reason,
+			"now":    now,
+		}
+
+		matches, err := evaluate[bool](rule.condition, vars)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = errors.Wrapf(err, "cannot evaluate condition of state rule %q", rule.name)
+			}
+
+			continue
+		}
+
+		if !matches || (!rule.override && rule.state <= state) {
+			continue
+		}
+
+		ruleReason := fmt.Sprintf("State set by rule %s.", rule.name)
+		if rule.reason != nil {
+			ruleReason, err = evaluate[string](rule.reason, vars)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = errors.Wrapf(err, "cannot evaluate reason of state rule %q", rule.name)
+				}
+
+				continue
+			}
+		}
+
+		state, reason = rule.state, ruleReason
+	}
+
+	entity.SetIcingaState(state, reason)
+
+	return firstErr
+}
+
+// evaluate evaluates the given program with the given variables, whose result must be of type T.
+func evaluate[T any](program cel.Program, vars map[string]any) (T, error) {
+	var zero T
+
+	out, _, err := program.Eval(vars)
+	if err != nil {
+		return zero, errors.WithStack(err)
+	}
+
+	v, ok := out.Value().(T)
+	if !ok {
+		return zero, errors.Errorf("expression evaluated to %T, not %T", out.Value(), zero)
+	}
+
+	return v, nil
+}
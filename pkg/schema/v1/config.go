@@ -21,4 +21,10 @@ const (
 	ConfigKeyPrometheusUrl                 ConfigKey = "prometheus.url"
 	ConfigKeyPrometheusUsername            ConfigKey = "prometheus.username"
 	ConfigKeyPrometheusPassword            ConfigKey = "prometheus.password"
+	ConfigKeyRetentionDisabled             ConfigKey = "retention.disabled"
 )
+
+// ConfigKeyRetention returns the config key of the given retention category, e.g. "retention.events".
+func ConfigKeyRetention(category string) ConfigKey {
+	return ConfigKey("retention." + category)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/icinga/icinga-go-library/types"
+	"github.com/icinga/icinga-kubernetes/pkg/notifications"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ktypes "k8s.io/apimachinery/pkg/types"
 	"reflect"
@@ -17,6 +18,13 @@ type Resource interface {
 	Obtain(k8s kmetav1.Object, clusterUuid types.UUID)
 }
 
+// IcingaStater is implemented by resources with an Icinga state,
+// so that it can be overridden after being obtained from the Kubernetes object, e.g. by state rules.
+type IcingaStater interface {
+	GetIcingaState() (IcingaState, string)
+	SetIcingaState(state IcingaState, reason string)
+}
+
 type Meta struct {
 	Uuid            types.UUID
 	ClusterUuid     types.UUID
@@ -25,6 +33,12 @@ type Meta struct {
 	Name            string
 	ResourceVersion string
 	Created         types.UnixMilli
+
+	// owner is the kind and name of the controlling owner, if any, which is used in notification events.
+	owner string
+
+	// silence is the silence of notification events defined by the annotations of the object.
+	silence notifications.Silence
 }
 
 func (m *Meta) ObtainMeta(k8s kmetav1.Object, clusterUuid types.UUID) {
@@ -35,6 +49,38 @@ func (m *Meta) ObtainMeta(k8s kmetav1.Object, clusterUuid types.UUID) {
 	m.Name = k8s.GetName()
 	m.ResourceVersion = k8s.GetResourceVersion()
 	m.Created = types.UnixMilli(k8s.GetCreationTimestamp().Time)
+
+	if owner := kmetav1.GetControllerOfNoCopy(k8s); owner != nil {
+		m.owner = owner.Kind + "/" + owner.Name
+	}
+
+	m.silence = notifications.ParseSilence(k8s.GetAnnotations())
+}
+
+// Silence implements the notifications.Silencer interface.
+func (m *Meta) Silence() notifications.Silence {
+	return m.silence
+}
+
+// eventTags returns the tags of notification events about the object, which are the same for all resources,
+// i.e. its UUID, Kubernetes kind, resource name, name and, if any, namespace and controlling owner.
+func (m *Meta) eventTags(kind, resource string) map[string]string {
+	tags := map[string]string{
+		"uuid":     m.Uuid.String(),
+		"kind":     kind,
+		"resource": resource,
+		"name":     m.Name,
+	}
+
+	if m.Namespace != "" {
+		tags["namespace"] = m.Namespace
+	}
+
+	if m.owner != "" {
+		tags["owner"] = m.owner
+	}
+
+	return tags
 }
 
 func (m *Meta) GetNamespace() string                           { return m.Namespace }
@@ -120,5 +166,6 @@ func NewNullableString(s any) sql.NullString {
 
 // Assert interface compliance.
 var (
-	_ kmetav1.Object = (*Meta)(nil)
+	_ kmetav1.Object         = (*Meta)(nil)
+	_ notifications.Silencer = (*Meta)(nil)
 )
@@ -2,16 +2,48 @@ package v1
 
 import (
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"github.com/icinga/icinga-go-library/types"
 	"github.com/icinga/icinga-kubernetes/pkg/database"
+	"github.com/icinga/icinga-kubernetes/pkg/notifications"
+	"github.com/robfig/cron/v3"
 	kbatchv1 "k8s.io/api/batch/v1"
+	kcorev1 "k8s.io/api/core/v1"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kruntime "k8s.io/apimachinery/pkg/runtime"
 	kserializer "k8s.io/apimachinery/pkg/runtime/serializer"
 	kjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	kcache "k8s.io/client-go/tools/cache"
+	"net/url"
+	"sort"
 	"strings"
+	"time"
 )
 
+// CronJobSuccessPeriods is the number of schedule periods after which
+// a CronJob without a successful run is considered to be failing.
+const CronJobSuccessPeriods = 3
+
+// CronJobCriticalFailedJobs is the number of consecutive failed jobs from which on a CronJob is critical.
+// Fewer failed jobs are a warning, as the next run may succeed again.
+const CronJobCriticalFailedJobs = 3
+
+// JobOwnerIndex is the name of the index of jobs by the UID of their controlling owner, e.g. a CronJob.
+const JobOwnerIndex = "owner-uid"
+
+// cronJobScheduleGrace is the delay granted to the CronJob controller to start a job
+// before the corresponding schedule is considered missed.
+const cronJobScheduleGrace = time.Minute
+
+// cronJobMaxMissedSchedules limits the number of missed schedules to count,
+// e.g. for CronJobs that run every minute but have not been scheduled for a long time.
+const cronJobMaxMissedSchedules = 100
+
+type CronJobFactory struct {
+	jobs kcache.Indexer
+}
+
 type CronJob struct {
 	Meta
 	Schedule                   string
@@ -25,12 +57,15 @@ type CronJob struct {
 	LastScheduleTime           types.UnixMilli
 	LastSuccessfulTime         types.UnixMilli
 	Yaml                       string
+	IcingaState                IcingaState
+	IcingaStateReason          string
 	Labels                     []Label              `db:"-"`
 	CronJobLabels              []CronJobLabel       `db:"-"`
 	ResourceLabels             []ResourceLabel      `db:"-"`
 	Annotations                []Annotation         `db:"-"`
 	CronJobAnnotations         []CronJobAnnotation  `db:"-"`
 	ResourceAnnotations        []ResourceAnnotation `db:"-"`
+	factory                    *CronJobFactory
 }
 
 type CronJobLabel struct {
@@ -43,8 +78,31 @@ type CronJobAnnotation struct {
 	AnnotationUuid types.UUID
 }
 
-func NewCronJob() Resource {
-	return &CronJob{}
+// NewCronJobFactory returns a new CronJobFactory that looks up the jobs of CronJobs via the given indexer,
+// which must have the JobOwnerIndex. The jobs are taken into account for the Icinga state of the CronJobs.
+func NewCronJobFactory(jobs kcache.Indexer) *CronJobFactory {
+	return &CronJobFactory{
+		jobs: jobs,
+	}
+}
+
+func (f *CronJobFactory) NewCronJob() Resource {
+	return &CronJob{factory: f}
+}
+
+// JobOwnerIndexFunc indexes jobs by the UID of their controlling owner.
+func JobOwnerIndexFunc(obj any) ([]string, error) {
+	job, ok := obj.(*kbatchv1.Job)
+	if !ok {
+		return nil, nil
+	}
+
+	owner := kmetav1.GetControllerOf(job)
+	if owner == nil {
+		return nil, nil
+	}
+
+	return []string{string(owner.UID)}, nil
 }
 
 func (c *CronJob) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
@@ -77,6 +135,7 @@ func (c *CronJob) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
 	if cronJob.Status.LastSuccessfulTime != nil {
 		c.LastSuccessfulTime = types.UnixMilli(cronJob.Status.LastSuccessfulTime.Time)
 	}
+	c.IcingaState, c.IcingaStateReason = c.getIcingaState(cronJob, time.Now())
 
 	for labelName, labelValue := range cronJob.Labels {
 		labelUuid := NewUUID(c.Uuid, strings.ToLower(labelName+":"+labelValue))
@@ -119,6 +178,212 @@ func (c *CronJob) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
 	c.Yaml = string(output)
 }
 
+func (c *CronJob) MarshalEvent() (notifications.Event, error) {
+	return notifications.Event{
+		Name:     c.Namespace + "/" + c.Name,
+		Severity: c.IcingaState.ToSeverity(),
+		Message:  c.IcingaStateReason,
+		URL:      &url.URL{Path: "/cronjob", RawQuery: fmt.Sprintf("id=%s", c.Uuid)},
+		Tags:     c.eventTags("CronJob", "cron_job"),
+	}, nil
+}
+
+// GetIcingaState implements the IcingaStater interface.
+func (c *CronJob) GetIcingaState() (IcingaState, string) {
+	return c.IcingaState, c.IcingaStateReason
+}
+
+// SetIcingaState implements the IcingaStater interface.
+func (c *CronJob) SetIcingaState(state IcingaState, reason string) {
+	c.IcingaState, c.IcingaStateReason = state, reason
+}
+
+func (c *CronJob) getIcingaState(cronJob *kbatchv1.CronJob, now time.Time) (IcingaState, string) {
+	if c.Suspend.Bool {
+		reason := fmt.Sprintf("CronJob %s/%s is suspended.", c.Namespace, c.Name)
+
+		return Ok, reason
+	}
+
+	spec := cronJob.Spec.Schedule
+	if cronJob.Spec.TimeZone != nil {
+		spec = "TZ=" + *cronJob.Spec.TimeZone + " " + spec
+	}
+
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		reason := fmt.Sprintf("CronJob %s/%s has an invalid schedule %q: %s.", c.Namespace, c.Name, spec, err)
+
+		return Critical, reason
+	}
+
+	if failed, lastFailed := c.consecutiveFailedJobs(cronJob); failed > 0 {
+		reason := fmt.Sprintf(
+			"CronJob %s/%s has %d consecutive failed jobs, most recently job %s: %s.",
+			c.Namespace, c.Name, failed, lastFailed.Name, jobFailure(lastFailed).Message)
+
+		if failed < CronJobCriticalFailedJobs {
+			return Warning, reason
+		}
+
+		return Critical, reason
+	}
+
+	next := schedule.Next(now)
+	period := schedule.Next(next).Sub(next)
+	lastSuccess := cronJob.CreationTimestamp.Time
+	if cronJob.Status.LastSuccessfulTime != nil {
+		lastSuccess = cronJob.Status.LastSuccessfulTime.Time
+	}
+	// Nothing runs while a CronJob is suspended, so it is only expected to succeed again after it has been resumed.
+	resumed := lastResumed(cronJob)
+	if resumed.After(lastSuccess) {
+		lastSuccess = resumed
+	}
+
+	if cronJob.Status.LastScheduleTime != nil && now.Sub(lastSuccess) > CronJobSuccessPeriods*period {
+		var reason string
+		if cronJob.Status.LastSuccessfulTime != nil {
+			reason = fmt.Sprintf(
+				"CronJob %s/%s has not completed successfully for more than %d schedule periods since %s.",
+				c.Namespace, c.Name, CronJobSuccessPeriods, lastSuccess.Format(time.RFC3339))
+		} else {
+			reason = fmt.Sprintf(
+				"CronJob %s/%s has never completed successfully within %d schedule periods.",
+				c.Namespace, c.Name, CronJobSuccessPeriods)
+		}
+
+		return Critical, reason
+	}
+
+	if missed, since := missedSchedules(schedule, cronJob, resumed, now); missed > 0 {
+		reason := fmt.Sprintf(
+			"CronJob %s/%s missed %d schedules since %s.", c.Namespace, c.Name, missed, since.Format(time.RFC3339))
+
+		return Warning, reason
+	}
+
+	reason := fmt.Sprintf("CronJob %s/%s runs as scheduled.", c.Namespace, c.Name)
+
+	return Ok, reason
+}
+
+// consecutiveFailedJobs returns the number of the most recent finished jobs of the given CronJob
+// that failed in a row and the most recent of them.
+func (c *CronJob) consecutiveFailedJobs(cronJob *kbatchv1.CronJob) (int, *kbatchv1.Job) {
+	if c.factory == nil {
+		return 0, nil
+	}
+
+	jobs, err := c.factory.jobs.ByIndex(JobOwnerIndex, string(cronJob.UID))
+	if err != nil {
+		return 0, nil
+	}
+
+	var finished []*kbatchv1.Job
+	for _, obj := range jobs {
+		job := obj.(*kbatchv1.Job)
+
+		for _, condition := range job.Status.Conditions {
+			if (condition.Type == kbatchv1.JobComplete || condition.Type == kbatchv1.JobFailed) &&
+				condition.Status == kcorev1.ConditionTrue {
+				finished = append(finished, job)
+
+				break
+			}
+		}
+	}
+
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[j].CreationTimestamp.Before(&finished[i].CreationTimestamp)
+	})
+
+	var failed int
+	for _, job := range finished {
+		if jobFailure(job) == nil {
+			break
+		}
+
+		failed++
+	}
+
+	if failed == 0 {
+		return 0, nil
+	}
+
+	return failed, finished[0]
+}
+
+// missedSchedules returns the number of schedules of the given CronJob since it was last scheduled or,
+// if it has never been scheduled, since its creation, for which no job has been started,
+// as well as the first of them. Schedules before the CronJob was last resumed are not considered.
+// Schedules for which a job can still be started, i.e. within the starting deadline, are not considered missed.
+// Neither are schedules that the controller skips on purpose while a job is still active and
+// the concurrency policy forbids concurrent runs.
+func missedSchedules(schedule cron.Schedule, cronJob *kbatchv1.CronJob, resumed, now time.Time) (int, time.Time) {
+	if cronJob.Spec.ConcurrencyPolicy == kbatchv1.ForbidConcurrent && len(cronJob.Status.Active) > 0 {
+		return 0, time.Time{}
+	}
+
+	earliest := cronJob.CreationTimestamp.Time
+	if cronJob.Status.LastScheduleTime != nil {
+		earliest = cronJob.Status.LastScheduleTime.Time
+	}
+	if resumed.After(earliest) {
+		earliest = resumed
+	}
+
+	deadline := cronJobScheduleGrace
+	if cronJob.Spec.StartingDeadlineSeconds != nil {
+		deadline = max(deadline, time.Duration(*cronJob.Spec.StartingDeadlineSeconds)*time.Second)
+	}
+
+	first := schedule.Next(earliest)
+
+	var missed int
+	for t := first; !t.After(now.Add(-deadline)) && missed < cronJobMaxMissedSchedules; t = schedule.Next(t) {
+		missed++
+	}
+
+	return missed, first
+}
+
+// lastResumed returns when the suspension of the given CronJob was last changed according to its managed fields,
+// which is when it was last resumed unless it is suspended. If unknown, the zero time is returned.
+func lastResumed(cronJob *kbatchv1.CronJob) time.Time {
+	var last time.Time
+	for _, entry := range cronJob.GetManagedFields() {
+		if entry.Subresource != "" || entry.Time == nil || entry.FieldsV1 == nil || !entry.Time.After(last) {
+			continue
+		}
+
+		var fields struct {
+			Spec map[string]json.RawMessage `json:"f:spec"`
+		}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+
+		if _, ok := fields.Spec["f:suspend"]; ok {
+			last = entry.Time.Time
+		}
+	}
+
+	return last
+}
+
+// jobFailure returns the failed condition of the given job or nil if the job has not failed.
+func jobFailure(job *kbatchv1.Job) *kbatchv1.JobCondition {
+	for i := range job.Status.Conditions {
+		condition := &job.Status.Conditions[i]
+		if condition.Type == kbatchv1.JobFailed && condition.Status == kcorev1.ConditionTrue {
+			return condition
+		}
+	}
+
+	return nil
+}
+
 func (c *CronJob) Relations() []database.Relation {
 	fk := database.WithForeignKey("cron_job_uuid")
 
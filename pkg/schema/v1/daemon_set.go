@@ -14,8 +14,13 @@ import (
 	ktypes "k8s.io/apimachinery/pkg/types"
 	"net/url"
 	"strings"
+	"time"
 )
 
+// daemonSetTemplateGenerationAnnotation is set by the API server to the generation of a daemon set
+// at which its pod template last changed.
+const daemonSetTemplateGenerationAnnotation = "deprecated.daemonset.template.generation"
+
 type DaemonSet struct {
 	Meta
 	UpdateStrategy         string
@@ -38,6 +43,10 @@ type DaemonSet struct {
 	Annotations            []Annotation          `db:"-"`
 	DaemonSetAnnotations   []DaemonSetAnnotation `db:"-"`
 	ResourceAnnotations    []ResourceAnnotation  `db:"-"`
+	Rollouts               []Rollout             `db:"-"`
+
+	// rollout is the status of the rollout of the daemon set.
+	rollout RolloutStatus
 }
 
 type DaemonSetCondition struct {
@@ -87,7 +96,8 @@ func (d *DaemonSet) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
 	d.UpdateNumberScheduled = daemonSet.Status.UpdatedNumberScheduled
 	d.NumberAvailable = daemonSet.Status.NumberAvailable
 	d.NumberUnavailable = daemonSet.Status.NumberUnavailable
-	d.IcingaState, d.IcingaStateReason = d.getIcingaState()
+	d.rollout = d.getRolloutStatus(daemonSet)
+	d.IcingaState, d.IcingaStateReason = d.getIcingaState(parseStateOverrides(daemonSet.Annotations))
 
 	for _, condition := range daemonSet.Status.Conditions {
 		d.Conditions = append(d.Conditions, DaemonSetCondition{
@@ -172,16 +182,41 @@ func (d *DaemonSet) MarshalEvent() (notifications.Event, error) {
 		Severity: d.IcingaState.ToSeverity(),
 		Message:  d.IcingaStateReason,
 		URL:      &url.URL{Path: "/daemonset", RawQuery: fmt.Sprintf("id=%s", d.Uuid)},
-		Tags: map[string]string{
-			"uuid":      d.Uuid.String(),
-			"name":      d.Name,
-			"namespace": d.Namespace,
-			"resource":  "daemon_set",
-		},
+		Tags:     d.rollout.eventTags(d.eventTags("DaemonSet", "daemon_set")),
 	}, nil
 }
 
-func (d *DaemonSet) getIcingaState() (IcingaState, string) {
+// GetIcingaState implements the IcingaStater interface.
+func (d *DaemonSet) GetIcingaState() (IcingaState, string) {
+	return d.IcingaState, d.IcingaStateReason
+}
+
+// SetIcingaState implements the IcingaStater interface.
+func (d *DaemonSet) SetIcingaState(state IcingaState, reason string) {
+	d.IcingaState, d.IcingaStateReason = state, reason
+}
+
+// GetRolloutStatus implements the Rolling interface.
+func (d *DaemonSet) GetRolloutStatus() RolloutStatus {
+	return d.rollout
+}
+
+// AddRollout implements the Rolling interface.
+func (d *DaemonSet) AddRollout(r Rollout) {
+	d.Rollouts = append(d.Rollouts, r)
+}
+
+func (d *DaemonSet) getIcingaState(overrides stateOverrides) (IcingaState, string) {
+	if d.rollout.Stalled {
+		return Critical, d.rollout.Message
+	}
+
+	// Problems other than the number of available replicas are critical and thus never better than
+	// the availability required via annotation, which only replaces the evaluation of the replica counts.
+	if state, reason, ok := overrides.availability("DaemonSet", d.Namespace, d.Name, d.NumberAvailable); ok {
+		return state, reason
+	}
+
 	if d.DesiredNumberScheduled < 1 {
 		reason := fmt.Sprintf("DaemonSet %s/%s has an invalid desired node count: %d.", d.Namespace, d.Name, d.DesiredNumberScheduled)
 
@@ -204,6 +239,41 @@ func (d *DaemonSet) getIcingaState() (IcingaState, string) {
 	}
 }
 
+func (d *DaemonSet) getRolloutStatus(daemonSet *kappsv1.DaemonSet) RolloutStatus {
+	// The template generation only changes if the pod template changes.
+	revision := daemonSet.Annotations[daemonSetTemplateGenerationAnnotation]
+
+	// Pods of daemon sets with the OnDelete strategy are only updated when they are deleted manually.
+	if daemonSet.Spec.UpdateStrategy.Type != kappsv1.RollingUpdateDaemonSetStrategyType {
+		return RolloutStatus{Generation: daemonSet.Generation, Revision: revision}
+	}
+
+	status := RolloutStatus{Generation: daemonSet.Generation, Revision: revision, Updating: true, Progressing: true}
+
+	switch {
+	case daemonSet.Status.ObservedGeneration < daemonSet.Generation:
+		// A new generation may not change the pod template, e.g. if only the update strategy changes.
+		status.Updating = false
+		status.Message = fmt.Sprintf("DaemonSet %s/%s is waiting for generation %d to be observed.", d.Namespace, d.Name, daemonSet.Generation)
+	case d.UpdateNumberScheduled < d.DesiredNumberScheduled:
+		status.Message = fmt.Sprintf("DaemonSet %s/%s is rolling out: %d out of %d new pods have been updated.", d.Namespace, d.Name, d.UpdateNumberScheduled, d.DesiredNumberScheduled)
+	case d.NumberAvailable < d.DesiredNumberScheduled:
+		status.Updating = false
+		status.Message = fmt.Sprintf("DaemonSet %s/%s is rolling out: %d out of %d updated pods are available.", d.Namespace, d.Name, d.NumberAvailable, d.DesiredNumberScheduled)
+	default:
+		return RolloutStatus{Generation: daemonSet.Generation, Revision: revision}
+	}
+
+	if status.Updating {
+		if since, stalled := stalledSince(daemonSet); stalled {
+			status.Stalled = true
+			status.Message = fmt.Sprintf("%s No progress has been made since %s.", status.Message, since.Format(time.RFC3339))
+		}
+	}
+
+	return status
+}
+
 func (d *DaemonSet) Relations() []database.Relation {
 	fk := database.WithForeignKey("daemon_set_uuid")
 
@@ -216,5 +286,6 @@ func (d *DaemonSet) Relations() []database.Relation {
 		database.HasMany(d.ResourceAnnotations, database.WithForeignKey("resource_uuid")),
 		database.HasMany(d.Annotations, database.WithoutCascadeDelete()),
 		database.HasMany(d.DaemonSetAnnotations, fk),
+		database.HasMany(d.Rollouts, database.WithForeignKey("resource_uuid"), database.WithoutCascadeDelete()),
 	}
 }
@@ -17,6 +17,10 @@ import (
 	"strings"
 )
 
+// deploymentRevisionAnnotation is set by the deployment controller to the revision of the newest ReplicaSet
+// of a deployment, which is only created if its pod template changes.
+const deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+
 type Deployment struct {
 	Meta
 	Strategy                string
@@ -40,6 +44,10 @@ type Deployment struct {
 	Annotations             []Annotation           `db:"-"`
 	DeploymentAnnotations   []DeploymentAnnotation `db:"-"`
 	ResourceAnnotations     []ResourceAnnotation   `db:"-"`
+	Rollouts                []Rollout              `db:"-"`
+
+	// rollout is the status of the rollout of the deployment.
+	rollout RolloutStatus
 }
 
 type DeploymentCondition struct {
@@ -98,7 +106,8 @@ func (d *Deployment) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
 	d.AvailableReplicas = deployment.Status.AvailableReplicas
 	d.ReadyReplicas = deployment.Status.ReadyReplicas
 	d.UnavailableReplicas = deployment.Status.UnavailableReplicas
-	d.IcingaState, d.IcingaStateReason = d.getIcingaState()
+	d.rollout = d.getRolloutStatus(deployment)
+	d.IcingaState, d.IcingaStateReason = d.getIcingaState(parseStateOverrides(deployment.Annotations))
 
 	for _, condition := range deployment.Status.Conditions {
 		d.Conditions = append(d.Conditions, DeploymentCondition{
@@ -184,24 +193,52 @@ func (d *Deployment) MarshalEvent() (notifications.Event, error) {
 		Severity: d.IcingaState.ToSeverity(),
 		Message:  d.IcingaStateReason,
 		URL:      &url.URL{Path: "/deployment", RawQuery: fmt.Sprintf("id=%s", d.Uuid)},
-		Tags: map[string]string{
-			"uuid":      d.Uuid.String(),
-			"name":      d.Name,
-			"namespace": d.Namespace,
-			"resource":  "deployment",
-		},
+		Tags:     d.rollout.eventTags(d.eventTags("Deployment", "deployment")),
 	}, nil
 }
 
-func (d *Deployment) getIcingaState() (IcingaState, string) {
+// GetIcingaState implements the IcingaStater interface.
+func (d *Deployment) GetIcingaState() (IcingaState, string) {
+	return d.IcingaState, d.IcingaStateReason
+}
+
+// SetIcingaState implements the IcingaStater interface.
+func (d *Deployment) SetIcingaState(state IcingaState, reason string) {
+	d.IcingaState, d.IcingaStateReason = state, reason
+}
+
+// GetRolloutStatus implements the Rolling interface.
+func (d *Deployment) GetRolloutStatus() RolloutStatus {
+	return d.rollout
+}
+
+// AddRollout implements the Rolling interface.
+func (d *Deployment) AddRollout(r Rollout) {
+	d.Rollouts = append(d.Rollouts, r)
+}
+
+func (d *Deployment) getIcingaState(overrides stateOverrides) (IcingaState, string) {
+	if d.rollout.Stalled {
+		return Critical, d.rollout.Message
+	}
+
 	for _, condition := range d.Conditions {
-		if condition.Type == string(kappsv1.DeploymentAvailable) && condition.Status != string(kcorev1.ConditionTrue) {
-			reason := fmt.Sprintf("Deployment %s/%s is not available: %s.", d.Namespace, d.Name, condition.Message)
+		if condition.Type == string(kappsv1.ReplicaSetReplicaFailure) && condition.Status != string(kcorev1.ConditionTrue) {
+			reason := fmt.Sprintf("Deployment %s/%s has replica failure: %s.", d.Namespace, d.Name, condition.Message)
 
 			return Critical, reason
 		}
-		if condition.Type == string(kappsv1.ReplicaSetReplicaFailure) && condition.Status != string(kcorev1.ConditionTrue) {
-			reason := fmt.Sprintf("Deployment %s/%s has replica failure: %s.", d.Namespace, d.Name, condition.Message)
+	}
+
+	// Problems other than the number of available replicas are critical and thus never better than
+	// the availability required via annotation, which only replaces the evaluation of the replica counts.
+	if state, reason, ok := overrides.availability("Deployment", d.Namespace, d.Name, d.AvailableReplicas); ok {
+		return state, reason
+	}
+
+	for _, condition := range d.Conditions {
+		if condition.Type == string(kappsv1.DeploymentAvailable) && condition.Status != string(kcorev1.ConditionTrue) {
+			reason := fmt.Sprintf("Deployment %s/%s is not available: %s.", d.Namespace, d.Name, condition.Message)
 
 			return Critical, reason
 		}
@@ -223,6 +260,44 @@ func (d *Deployment) getIcingaState() (IcingaState, string) {
 	}
 }
 
+func (d *Deployment) getRolloutStatus(deployment *kappsv1.Deployment) RolloutStatus {
+	// The revision is that of the newest ReplicaSet, which is only created if the pod template changes.
+	revision := deployment.Annotations[deploymentRevisionAnnotation]
+	status := RolloutStatus{Generation: deployment.Generation, Revision: revision, Updating: true, Progressing: true}
+
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		// A new generation may just scale the deployment, which is not a rollout.
+		status.Updating = false
+		status.Message = fmt.Sprintf("Deployment %s/%s is waiting for generation %d to be observed.", d.Namespace, d.Name, deployment.Generation)
+
+		return status
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		// Kubernetes does not export the reason of exceeded progress deadlines.
+		if condition.Type == kappsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			status.Stalled = true
+			status.Message = fmt.Sprintf("Deployment %s/%s rollout is stalled: %s.", d.Namespace, d.Name, condition.Message)
+
+			return status
+		}
+	}
+
+	switch {
+	case d.UpdatedReplicas < d.DesiredReplicas:
+		status.Message = fmt.Sprintf("Deployment %s/%s is rolling out: %d out of %d new replicas have been updated.", d.Namespace, d.Name, d.UpdatedReplicas, d.DesiredReplicas)
+	case d.ActualReplicas > d.UpdatedReplicas:
+		status.Message = fmt.Sprintf("Deployment %s/%s is rolling out: %d old replicas are pending termination.", d.Namespace, d.Name, d.ActualReplicas-d.UpdatedReplicas)
+	case d.AvailableReplicas < d.UpdatedReplicas:
+		status.Updating = false
+		status.Message = fmt.Sprintf("Deployment %s/%s is rolling out: %d out of %d updated replicas are available.", d.Namespace, d.Name, d.AvailableReplicas, d.UpdatedReplicas)
+	default:
+		return RolloutStatus{Generation: deployment.Generation, Revision: revision}
+	}
+
+	return status
+}
+
 func (d *Deployment) Relations() []database.Relation {
 	fk := database.WithForeignKey("deployment_uuid")
 
@@ -235,5 +310,6 @@ func (d *Deployment) Relations() []database.Relation {
 		database.HasMany(d.ResourceAnnotations, database.WithForeignKey("resource_uuid")),
 		database.HasMany(d.Annotations, database.WithoutCascadeDelete()),
 		database.HasMany(d.DeploymentAnnotations, fk),
+		database.HasMany(d.Rollouts, database.WithForeignKey("resource_uuid"), database.WithoutCascadeDelete()),
 	}
 }
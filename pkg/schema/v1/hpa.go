@@ -0,0 +1,346 @@
+package v1
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/icinga/icinga-go-library/types"
+	"github.com/icinga/icinga-kubernetes/pkg/database"
+	"github.com/icinga/icinga-kubernetes/pkg/notifications"
+	kautoscalingv2 "k8s.io/api/autoscaling/v2"
+	kcorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	kserializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	kjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"net/url"
+	"strings"
+)
+
+type Hpa struct {
+	Meta
+	ScaleTargetKind     string
+	ScaleTargetName     string
+	MinReplicas         int32
+	MaxReplicas         int32
+	CurrentReplicas     int32
+	DesiredReplicas     int32
+	LastScale           types.UnixMilli
+	Yaml                string
+	IcingaState         IcingaState
+	IcingaStateReason   string
+	Metrics             []HpaMetric          `db:"-"`
+	Conditions          []HpaCondition       `db:"-"`
+	Labels              []Label              `db:"-"`
+	HpaLabels           []HpaLabel           `db:"-"`
+	ResourceLabels      []ResourceLabel      `db:"-"`
+	Annotations         []Annotation         `db:"-"`
+	HpaAnnotations      []HpaAnnotation      `db:"-"`
+	ResourceAnnotations []ResourceAnnotation `db:"-"`
+}
+
+// HpaMetric is a metric the HPA scales on, i.e. its target as well as its current value if known.
+// Utilization targets are stored as a percentage of the requested resources,
+// all other targets as quantity strings.
+type HpaMetric struct {
+	Uuid               types.UUID
+	HpaUuid            types.UUID
+	Type               string
+	Name               string
+	Container          sql.NullString
+	TargetType         string
+	TargetUtilization  sql.NullInt32
+	TargetValue        sql.NullString
+	CurrentUtilization sql.NullInt32
+	CurrentValue       sql.NullString
+}
+
+type HpaCondition struct {
+	HpaUuid        types.UUID
+	Type           string
+	Status         string
+	LastTransition types.UnixMilli
+	Reason         string
+	Message        string
+}
+
+type HpaLabel struct {
+	HpaUuid   types.UUID
+	LabelUuid types.UUID
+}
+
+type HpaAnnotation struct {
+	HpaUuid        types.UUID
+	AnnotationUuid types.UUID
+}
+
+func NewHpa() Resource {
+	return &Hpa{}
+}
+
+func (h *Hpa) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
+	h.ObtainMeta(k8s, clusterUuid)
+
+	hpa := k8s.(*kautoscalingv2.HorizontalPodAutoscaler)
+
+	h.ScaleTargetKind = hpa.Spec.ScaleTargetRef.Kind
+	h.ScaleTargetName = hpa.Spec.ScaleTargetRef.Name
+	// Kubernetes defaults minReplicas to 1 if not configured.
+	h.MinReplicas = 1
+	if hpa.Spec.MinReplicas != nil {
+		h.MinReplicas = *hpa.Spec.MinReplicas
+	}
+	h.MaxReplicas = hpa.Spec.MaxReplicas
+	h.CurrentReplicas = hpa.Status.CurrentReplicas
+	h.DesiredReplicas = hpa.Status.DesiredReplicas
+	if hpa.Status.LastScaleTime != nil {
+		h.LastScale = types.UnixMilli(hpa.Status.LastScaleTime.Time)
+	}
+
+	currentMetrics := make(map[string]kautoscalingv2.MetricValueStatus, len(hpa.Status.CurrentMetrics))
+	for _, metric := range hpa.Status.CurrentMetrics {
+		key, current := hpaMetricStatus(metric)
+		currentMetrics[key] = current
+	}
+
+	for _, metric := range hpa.Spec.Metrics {
+		key, name, container, target := hpaMetricSpec(metric)
+		m := HpaMetric{
+			Uuid:       NewUUID(h.Uuid, key),
+			HpaUuid:    h.Uuid,
+			Type:       string(metric.Type),
+			Name:       name,
+			Container:  NewNullableString(container),
+			TargetType: string(target.Type),
+		}
+
+		if target.AverageUtilization != nil {
+			m.TargetUtilization = sql.NullInt32{Int32: *target.AverageUtilization, Valid: true}
+		}
+		m.TargetValue = hpaQuantity(target.Value, target.AverageValue)
+
+		if current, ok := currentMetrics[key]; ok {
+			if current.AverageUtilization != nil {
+				m.CurrentUtilization = sql.NullInt32{Int32: *current.AverageUtilization, Valid: true}
+			}
+			m.CurrentValue = hpaQuantity(current.Value, current.AverageValue)
+		}
+
+		h.Metrics = append(h.Metrics, m)
+	}
+
+	for _, condition := range hpa.Status.Conditions {
+		h.Conditions = append(h.Conditions, HpaCondition{
+			HpaUuid:        h.Uuid,
+			Type:           string(condition.Type),
+			Status:         string(condition.Status),
+			LastTransition: types.UnixMilli(condition.LastTransitionTime.Time),
+			Reason:         condition.Reason,
+			Message:        condition.Message,
+		})
+	}
+
+	h.IcingaState, h.IcingaStateReason = h.getIcingaState()
+
+	for labelName, labelValue := range hpa.Labels {
+		labelUuid := NewUUID(h.Uuid, strings.ToLower(labelName+":"+labelValue))
+		h.Labels = append(h.Labels, Label{
+			Uuid:  labelUuid,
+			Name:  labelName,
+			Value: labelValue,
+		})
+		h.HpaLabels = append(h.HpaLabels, HpaLabel{
+			HpaUuid:   h.Uuid,
+			LabelUuid: labelUuid,
+		})
+		h.ResourceLabels = append(h.ResourceLabels, ResourceLabel{
+			ResourceUuid: h.Uuid,
+			LabelUuid:    labelUuid,
+		})
+	}
+
+	for annotationName, annotationValue := range hpa.Annotations {
+		annotationUuid := NewUUID(h.Uuid, strings.ToLower(annotationName+":"+annotationValue))
+		h.Annotations = append(h.Annotations, Annotation{
+			Uuid:  annotationUuid,
+			Name:  annotationName,
+			Value: annotationValue,
+		})
+		h.HpaAnnotations = append(h.HpaAnnotations, HpaAnnotation{
+			HpaUuid:        h.Uuid,
+			AnnotationUuid: annotationUuid,
+		})
+		h.ResourceAnnotations = append(h.ResourceAnnotations, ResourceAnnotation{
+			ResourceUuid:   h.Uuid,
+			AnnotationUuid: annotationUuid,
+		})
+	}
+
+	scheme := kruntime.NewScheme()
+	_ = kautoscalingv2.AddToScheme(scheme)
+	codec := kserializer.NewCodecFactory(scheme).EncoderForVersion(kjson.NewYAMLSerializer(kjson.DefaultMetaFactory, scheme, scheme), kautoscalingv2.SchemeGroupVersion)
+	output, _ := kruntime.Encode(codec, hpa)
+	h.Yaml = string(output)
+}
+
+func (h *Hpa) MarshalEvent() (notifications.Event, error) {
+	return notifications.Event{
+		Name:     h.Namespace + "/" + h.Name,
+		Severity: h.IcingaState.ToSeverity(),
+		Message:  h.IcingaStateReason,
+		URL:      &url.URL{Path: "/hpa", RawQuery: fmt.Sprintf("id=%s", h.Uuid)},
+		Tags:     h.eventTags("HorizontalPodAutoscaler", "hpa"),
+	}, nil
+}
+
+// GetIcingaState implements the IcingaStater interface.
+func (h *Hpa) GetIcingaState() (IcingaState, string) {
+	return h.IcingaState, h.IcingaStateReason
+}
+
+// SetIcingaState implements the IcingaStater interface.
+func (h *Hpa) SetIcingaState(state IcingaState, reason string) {
+	h.IcingaState, h.IcingaStateReason = state, reason
+}
+
+func (h *Hpa) getIcingaState() (IcingaState, string) {
+	if len(h.Conditions) == 0 {
+		reason := fmt.Sprintf("HPA %s/%s has not been evaluated yet.", h.Namespace, h.Name)
+
+		return Pending, reason
+	}
+
+	for _, condition := range h.Conditions {
+		if condition.Status != string(kcorev1.ConditionFalse) {
+			continue
+		}
+
+		switch condition.Type {
+		case string(kautoscalingv2.AbleToScale):
+			reason := fmt.Sprintf("HPA %s/%s is not able to scale: %s.", h.Namespace, h.Name, condition.Message)
+
+			return Critical, reason
+		case string(kautoscalingv2.ScalingActive):
+			// The HPA deactivates itself if its target has been scaled to zero,
+			// which is intentional and therefore not a problem.
+			if condition.Reason == "ScalingDisabled" {
+				reason := fmt.Sprintf("HPA %s/%s is disabled: %s.", h.Namespace, h.Name, condition.Message)
+
+				return Ok, reason
+			}
+
+			reason := fmt.Sprintf("HPA %s/%s cannot fetch metrics: %s.", h.Namespace, h.Name, condition.Message)
+
+			return Critical, reason
+		}
+	}
+
+	// A desired number of replicas above the maximum, i.e. TooManyReplicas, is reported with its message.
+	for _, condition := range h.Conditions {
+		if condition.Type == string(kautoscalingv2.ScalingLimited) &&
+			condition.Status == string(kcorev1.ConditionTrue) &&
+			condition.Reason == "TooManyReplicas" {
+			reason := fmt.Sprintf("HPA %s/%s is limited by its maximum of %d replicas: %s.",
+				h.Namespace, h.Name, h.MaxReplicas, condition.Message)
+
+			return Warning, reason
+		}
+	}
+
+	// Running at the maximum leaves no headroom for further load, even if it is the desired number of replicas.
+	// HPAs that intentionally run at their maximum, e.g. with the minimum equal to the maximum,
+	// can be excluded via state rules.
+	if h.CurrentReplicas >= h.MaxReplicas {
+		reason := fmt.Sprintf(
+			"HPA %s/%s is pinned at its maximum of %d replicas and cannot scale up any further.",
+			h.Namespace, h.Name, h.MaxReplicas)
+
+		return Warning, reason
+	}
+
+	reason := fmt.Sprintf(
+		"HPA %s/%s has %d replicas, which is within its range of %d to %d replicas.",
+		h.Namespace, h.Name, h.CurrentReplicas, h.MinReplicas, h.MaxReplicas)
+
+	return Ok, reason
+}
+
+func (h *Hpa) Relations() []database.Relation {
+	fk := database.WithForeignKey("hpa_uuid")
+
+	return []database.Relation{
+		database.HasMany(h.Metrics, fk),
+		database.HasMany(h.Conditions, fk),
+		database.HasMany(h.ResourceLabels, database.WithForeignKey("resource_uuid")),
+		database.HasMany(h.Labels, database.WithoutCascadeDelete()),
+		database.HasMany(h.HpaLabels, fk),
+		database.HasMany(h.ResourceAnnotations, database.WithForeignKey("resource_uuid")),
+		database.HasMany(h.Annotations, database.WithoutCascadeDelete()),
+		database.HasMany(h.HpaAnnotations, fk),
+	}
+}
+
+// hpaMetricSpec returns the identifying key, name, container and target of the given metric spec.
+// The key matches the one returned by hpaMetricStatus for the corresponding metric status.
+func hpaMetricSpec(metric kautoscalingv2.MetricSpec) (key, name, container string, target kautoscalingv2.MetricTarget) {
+	var object kautoscalingv2.CrossVersionObjectReference
+
+	switch metric.Type {
+	case kautoscalingv2.ResourceMetricSourceType:
+		name, target = string(metric.Resource.Name), metric.Resource.Target
+	case kautoscalingv2.ContainerResourceMetricSourceType:
+		name, target = string(metric.ContainerResource.Name), metric.ContainerResource.Target
+		container = metric.ContainerResource.Container
+	case kautoscalingv2.PodsMetricSourceType:
+		name, target = metric.Pods.Metric.Name, metric.Pods.Target
+	case kautoscalingv2.ObjectMetricSourceType:
+		name, target = metric.Object.Metric.Name, metric.Object.Target
+		object = metric.Object.DescribedObject
+	case kautoscalingv2.ExternalMetricSourceType:
+		name, target = metric.External.Metric.Name, metric.External.Target
+	}
+
+	return hpaMetricKey(metric.Type, name, container, object), name, container, target
+}
+
+// hpaMetricStatus returns the identifying key and current value of the given metric status.
+func hpaMetricStatus(metric kautoscalingv2.MetricStatus) (string, kautoscalingv2.MetricValueStatus) {
+	var name, container string
+	var object kautoscalingv2.CrossVersionObjectReference
+	var current kautoscalingv2.MetricValueStatus
+
+	switch metric.Type {
+	case kautoscalingv2.ResourceMetricSourceType:
+		name, current = string(metric.Resource.Name), metric.Resource.Current
+	case kautoscalingv2.ContainerResourceMetricSourceType:
+		name, current = string(metric.ContainerResource.Name), metric.ContainerResource.Current
+		container = metric.ContainerResource.Container
+	case kautoscalingv2.PodsMetricSourceType:
+		name, current = metric.Pods.Metric.Name, metric.Pods.Current
+	case kautoscalingv2.ObjectMetricSourceType:
+		name, current = metric.Object.Metric.Name, metric.Object.Current
+		object = metric.Object.DescribedObject
+	case kautoscalingv2.ExternalMetricSourceType:
+		name, current = metric.External.Metric.Name, metric.External.Current
+	}
+
+	return hpaMetricKey(metric.Type, name, container, object), current
+}
+
+func hpaMetricKey(
+	metricType kautoscalingv2.MetricSourceType, name, container string, object kautoscalingv2.CrossVersionObjectReference,
+) string {
+	return strings.ToLower(strings.Join(
+		[]string{string(metricType), name, container, object.Kind, object.Name}, ":"))
+}
+
+// hpaQuantity returns the first non-nil quantity as string.
+func hpaQuantity(quantities ...*resource.Quantity) sql.NullString {
+	for _, q := range quantities {
+		if q != nil {
+			return sql.NullString{String: q.String(), Valid: true}
+		}
+	}
+
+	return sql.NullString{}
+}
@@ -1,8 +1,11 @@
 package v1
 
 import (
+	"database/sql"
 	"database/sql/driver"
 	"fmt"
+	"github.com/pkg/errors"
+	"strings"
 )
 
 type IcingaState uint8
@@ -32,6 +35,46 @@ func (s IcingaState) String() string {
 	}
 }
 
+// ParseIcingaState returns the Icinga state of the given name, e.g. critical.
+func ParseIcingaState(state string) (IcingaState, error) {
+	switch strings.ToLower(state) {
+	case "ok":
+		return Ok, nil
+	case "warning":
+		return Warning, nil
+	case "critical":
+		return Critical, nil
+	case "unknown":
+		return Unknown, nil
+	case "pending":
+		return Pending, nil
+	default:
+		return 0, errors.Errorf("invalid Icinga state %q", state)
+	}
+}
+
+// Scan implements the sql.Scanner interface.
+func (s *IcingaState) Scan(src any) error {
+	var state string
+	switch v := src.(type) {
+	case string:
+		state = v
+	case []byte:
+		state = string(v)
+	default:
+		return errors.Errorf("cannot scan Icinga state from %T", src)
+	}
+
+	parsed, err := ParseIcingaState(state)
+	if err != nil {
+		return err
+	}
+
+	*s = parsed
+
+	return nil
+}
+
 // Value implements the driver.Valuer interface.
 func (s IcingaState) Value() (driver.Value, error) {
 	return s.String(), nil
@@ -57,5 +100,6 @@ func (s IcingaState) ToSeverity() string {
 // Assert interface compliance.
 var (
 	_ fmt.Stringer  = (*IcingaState)(nil)
+	_ sql.Scanner   = (*IcingaState)(nil)
 	_ driver.Valuer = (*IcingaState)(nil)
 )
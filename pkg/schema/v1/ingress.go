@@ -2,19 +2,33 @@ package v1
 
 import (
 	"database/sql"
+	"fmt"
 	"github.com/icinga/icinga-go-library/types"
 	"github.com/icinga/icinga-kubernetes/pkg/database"
+	"github.com/icinga/icinga-kubernetes/pkg/notifications"
+	kcorev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kruntime "k8s.io/apimachinery/pkg/runtime"
 	kserializer "k8s.io/apimachinery/pkg/runtime/serializer"
 	kjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	kcorelistersv1 "k8s.io/client-go/listers/core/v1"
+	kdiscoverylistersv1 "k8s.io/client-go/listers/discovery/v1"
+	"net/url"
 	"strings"
 )
 
+type IngressFactory struct {
+	services       kcorelistersv1.ServiceLister
+	endpointSlices kdiscoverylistersv1.EndpointSliceLister
+}
+
 type Ingress struct {
 	Meta
 	Yaml                   string
+	IcingaState            IcingaState
+	IcingaStateReason      string
 	IngressTls             []IngressTls             `db:"-"`
 	IngressBackendService  []IngressBackendService  `db:"-"`
 	IngressBackendResource []IngressBackendResource `db:"-"`
@@ -25,6 +39,7 @@ type Ingress struct {
 	Annotations            []Annotation             `db:"-"`
 	IngressAnnotations     []IngressAnnotation      `db:"-"`
 	ResourceAnnotations    []ResourceAnnotation     `db:"-"`
+	factory                *IngressFactory
 }
 
 type IngressTls struct {
@@ -70,8 +85,19 @@ type IngressAnnotation struct {
 	AnnotationUuid types.UUID
 }
 
-func NewIngress() Resource {
-	return &Ingress{}
+// NewIngressFactory returns a new IngressFactory that looks up the backend services of ingresses
+// and their endpoints via the given listers, which are taken into account for the Icinga state of the ingresses.
+func NewIngressFactory(
+	services kcorelistersv1.ServiceLister, endpointSlices kdiscoverylistersv1.EndpointSliceLister,
+) *IngressFactory {
+	return &IngressFactory{
+		services:       services,
+		endpointSlices: endpointSlices,
+	}
+}
+
+func (f *IngressFactory) NewIngress() Resource {
+	return &Ingress{factory: f}
 }
 
 func (i *Ingress) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
@@ -207,6 +233,8 @@ func (i *Ingress) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
 		})
 	}
 
+	i.IcingaState, i.IcingaStateReason = i.getIcingaState()
+
 	scheme := kruntime.NewScheme()
 	_ = networkingv1.AddToScheme(scheme)
 	codec := kserializer.NewCodecFactory(scheme).EncoderForVersion(kjson.NewYAMLSerializer(kjson.DefaultMetaFactory, scheme, scheme), networkingv1.SchemeGroupVersion)
@@ -214,6 +242,98 @@ func (i *Ingress) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
 	i.Yaml = string(output)
 }
 
+func (i *Ingress) MarshalEvent() (notifications.Event, error) {
+	return notifications.Event{
+		Name:     i.Namespace + "/" + i.Name,
+		Severity: i.IcingaState.ToSeverity(),
+		Message:  i.IcingaStateReason,
+		URL:      &url.URL{Path: "/ingress", RawQuery: fmt.Sprintf("id=%s", i.Uuid)},
+		Tags:     i.eventTags("Ingress", "ingress"),
+	}, nil
+}
+
+// GetIcingaState implements the IcingaStater interface.
+func (i *Ingress) GetIcingaState() (IcingaState, string) {
+	return i.IcingaState, i.IcingaStateReason
+}
+
+// SetIcingaState implements the IcingaStater interface.
+func (i *Ingress) SetIcingaState(state IcingaState, reason string) {
+	i.IcingaState, i.IcingaStateReason = state, reason
+}
+
+func (i *Ingress) getIcingaState() (IcingaState, string) {
+	if i.factory == nil {
+		reason := fmt.Sprintf("Ingress %s/%s is ok.", i.Namespace, i.Name)
+
+		return Ok, reason
+	}
+
+	var backends []string
+	seen := make(map[string]struct{})
+	for _, backend := range i.IngressBackendService {
+		if _, ok := seen[backend.ServiceName]; !ok {
+			seen[backend.ServiceName] = struct{}{}
+			backends = append(backends, backend.ServiceName)
+		}
+	}
+
+	if len(backends) == 0 {
+		reason := fmt.Sprintf("Ingress %s/%s does not have any backend services.", i.Namespace, i.Name)
+
+		return Ok, reason
+	}
+
+	var problems []string
+	for _, backend := range backends {
+		service, err := i.factory.services.Services(i.Namespace).Get(backend)
+		if err != nil {
+			if kerrors.IsNotFound(err) {
+				problems = append(problems, fmt.Sprintf("backend service %s does not exist", backend))
+
+				continue
+			}
+
+			reason := fmt.Sprintf("Ingress %s/%s cannot be evaluated: %s.", i.Namespace, i.Name, err)
+
+			return Unknown, reason
+		}
+
+		if service.Spec.Type == kcorev1.ServiceTypeExternalName {
+			continue
+		}
+
+		ready, err := readyEndpoints(i.factory.endpointSlices, i.Namespace, backend)
+		if err != nil {
+			reason := fmt.Sprintf("Ingress %s/%s cannot be evaluated: %s.", i.Namespace, i.Name, err)
+
+			return Unknown, reason
+		}
+
+		if ready == 0 {
+			problems = append(problems, fmt.Sprintf("backend service %s does not have any ready endpoints", backend))
+		}
+	}
+
+	switch {
+	case len(problems) == len(backends):
+		reason := fmt.Sprintf(
+			"Ingress %s/%s does not have any reachable backends: %s.", i.Namespace, i.Name, strings.Join(problems, ", "))
+
+		return Critical, reason
+	case len(problems) > 0:
+		reason := fmt.Sprintf(
+			"Ingress %s/%s has unreachable backends: %s.", i.Namespace, i.Name, strings.Join(problems, ", "))
+
+		return Warning, reason
+	default:
+		reason := fmt.Sprintf(
+			"Ingress %s/%s has %d reachable backend services.", i.Namespace, i.Name, len(backends))
+
+		return Ok, reason
+	}
+}
+
 func (i *Ingress) Relations() []database.Relation {
 	fk := database.WithForeignKey("ingress_uuid")
 
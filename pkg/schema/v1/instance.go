@@ -13,9 +13,16 @@ type Instance struct {
 	KubernetesHeartbeat    types.UnixMilli
 	KubernetesApiReachable types.Bool
 	Message                sql.NullString
+	Role                   string
 	Heartbeat              types.UnixMilli
 }
 
+// Roles of instances, which are always leaders unless leader election is enabled.
+const (
+	InstanceRoleLeader   = "leader"
+	InstanceRoleFollower = "follower"
+)
+
 func (Instance) TableName() string {
 	return "kubernetes_instance"
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/icinga/icinga-go-library/strcase"
 	"github.com/icinga/icinga-go-library/types"
 	"github.com/icinga/icinga-kubernetes/pkg/database"
+	"github.com/icinga/icinga-kubernetes/pkg/notifications"
 	kbatchv1 "k8s.io/api/batch/v1"
 	kcorev1 "k8s.io/api/core/v1"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -13,6 +14,7 @@ import (
 	kserializer "k8s.io/apimachinery/pkg/runtime/serializer"
 	kjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
 	ktypes "k8s.io/apimachinery/pkg/types"
+	"net/url"
 	"strings"
 )
 
@@ -218,6 +220,26 @@ func (j *Job) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
 	j.Yaml = string(output)
 }
 
+func (j *Job) MarshalEvent() (notifications.Event, error) {
+	return notifications.Event{
+		Name:     j.Namespace + "/" + j.Name,
+		Severity: j.IcingaState.ToSeverity(),
+		Message:  j.IcingaStateReason,
+		URL:      &url.URL{Path: "/job", RawQuery: fmt.Sprintf("id=%s", j.Uuid)},
+		Tags:     j.eventTags("Job", "job"),
+	}, nil
+}
+
+// GetIcingaState implements the IcingaStater interface.
+func (j *Job) GetIcingaState() (IcingaState, string) {
+	return j.IcingaState, j.IcingaStateReason
+}
+
+// SetIcingaState implements the IcingaStater interface.
+func (j *Job) SetIcingaState(state IcingaState, reason string) {
+	j.IcingaState, j.IcingaStateReason = state, reason
+}
+
 func (j *Job) getIcingaState(job *kbatchv1.Job) (IcingaState, string) {
 	for _, condition := range job.Status.Conditions {
 		if condition.Status != kcorev1.ConditionTrue {
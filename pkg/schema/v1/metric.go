@@ -26,6 +26,11 @@ func (m *PrometheusClusterMetric) Fingerprint() database.Fingerprinter {
 	return m
 }
 
+// Upsert implements the database.Upserter interface.
+func (m *PrometheusClusterMetric) Upsert() any {
+	return metricUpserter{Value: m.Value}
+}
+
 type PrometheusNodeMetric struct {
 	NodeUuid  types.UUID
 	Timestamp int64
@@ -46,6 +51,11 @@ func (m *PrometheusNodeMetric) Fingerprint() database.Fingerprinter {
 	return m
 }
 
+// Upsert implements the database.Upserter interface.
+func (m *PrometheusNodeMetric) Upsert() any {
+	return metricUpserter{Value: m.Value}
+}
+
 type PrometheusPodMetric struct {
 	PodUuid   types.UUID
 	Timestamp int64
@@ -66,6 +76,11 @@ func (m *PrometheusPodMetric) Fingerprint() database.Fingerprinter {
 	return m
 }
 
+// Upsert implements the database.Upserter interface.
+func (m *PrometheusPodMetric) Upsert() any {
+	return metricUpserter{Value: m.Value}
+}
+
 type PrometheusContainerMetric struct {
 	ContainerUuid types.UUID
 	Timestamp     int64
@@ -86,6 +101,16 @@ func (m *PrometheusContainerMetric) Fingerprint() database.Fingerprinter {
 	return m
 }
 
+// Upsert implements the database.Upserter interface.
+func (m *PrometheusContainerMetric) Upsert() any {
+	return metricUpserter{Value: m.Value}
+}
+
+// metricUpserter defines the columns which are updated if a metric with the same primary key already exists.
+type metricUpserter struct {
+	Value float64
+}
+
 type compoundId struct {
 	id string
 }
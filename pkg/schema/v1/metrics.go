@@ -1,11 +1,13 @@
 package v1
 
 import (
+	"github.com/icinga/icinga-go-library/database"
 	"github.com/icinga/icinga-go-library/types"
 	"time"
 )
 
 type PodMetrics struct {
+	ClusterUuid           types.UUID      `db:"cluster_uuid"`
 	Namespace             string          `db:"namespace"`
 	PodName               string          `db:"pod_name"`
 	ContainerName         string          `db:"container_name"`
@@ -16,3 +18,37 @@ type PodMetrics struct {
 	StorageUsage          float64         `db:"storage_usage"`
 	EphemeralStorageUsage float64         `db:"ephemeral_storage_usage"`
 }
+
+func (m *PodMetrics) ID() database.ID {
+	return compoundId{id: m.ClusterUuid.String() + m.Namespace + "/" + m.PodName + "/" + m.ContainerName}
+}
+
+func (m *PodMetrics) SetID(id database.ID) {
+	panic("Not expected to be called")
+}
+
+func (m *PodMetrics) Fingerprint() database.Fingerprinter {
+	return m
+}
+
+// NodeMetrics is the resource usage of a node as reported by the metrics API.
+type NodeMetrics struct {
+	ClusterUuid types.UUID      `db:"cluster_uuid"`
+	NodeName    string          `db:"node_name"`
+	Timestamp   types.UnixMilli `db:"timestamp"`
+	Duration    time.Duration   `db:"duration"`
+	CPUUsage    float64         `db:"cpu_usage"`
+	MemoryUsage float64         `db:"memory_usage"`
+}
+
+func (m *NodeMetrics) ID() database.ID {
+	return compoundId{id: m.ClusterUuid.String() + m.NodeName}
+}
+
+func (m *NodeMetrics) SetID(id database.ID) {
+	panic("Not expected to be called")
+}
+
+func (m *NodeMetrics) Fingerprint() database.Fingerprinter {
+	return m
+}
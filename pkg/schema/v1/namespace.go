@@ -1,13 +1,16 @@
 package v1
 
 import (
+	"fmt"
 	"github.com/icinga/icinga-go-library/types"
 	"github.com/icinga/icinga-kubernetes/pkg/database"
+	"github.com/icinga/icinga-kubernetes/pkg/notifications"
 	kcorev1 "k8s.io/api/core/v1"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kruntime "k8s.io/apimachinery/pkg/runtime"
 	kserializer "k8s.io/apimachinery/pkg/runtime/serializer"
 	kjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"net/url"
 	"strings"
 )
 
@@ -15,6 +18,8 @@ type Namespace struct {
 	Meta
 	Phase                string
 	Yaml                 string
+	IcingaState          IcingaState
+	IcingaStateReason    string
 	Conditions           []NamespaceCondition  `db:"-"`
 	Labels               []Label               `db:"-"`
 	NamespaceLabels      []NamespaceLabel      `db:"-"`
@@ -65,6 +70,8 @@ func (n *Namespace) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
 		})
 	}
 
+	n.IcingaState, n.IcingaStateReason = n.getIcingaState(namespace)
+
 	for labelName, labelValue := range namespace.Labels {
 		labelUuid := NewUUID(n.Uuid, strings.ToLower(labelName+":"+labelValue))
 		n.Labels = append(n.Labels, Label{
@@ -106,6 +113,48 @@ func (n *Namespace) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
 	n.Yaml = string(output)
 }
 
+func (n *Namespace) MarshalEvent() (notifications.Event, error) {
+	return notifications.Event{
+		Name:     n.Name,
+		Severity: n.IcingaState.ToSeverity(),
+		Message:  n.IcingaStateReason,
+		URL:      &url.URL{Path: "/namespace", RawQuery: fmt.Sprintf("id=%s", n.Uuid)},
+		Tags:     n.eventTags("Namespace", "namespace"),
+	}, nil
+}
+
+// GetIcingaState implements the IcingaStater interface.
+func (n *Namespace) GetIcingaState() (IcingaState, string) {
+	return n.IcingaState, n.IcingaStateReason
+}
+
+// SetIcingaState implements the IcingaStater interface.
+func (n *Namespace) SetIcingaState(state IcingaState, reason string) {
+	n.IcingaState, n.IcingaStateReason = state, reason
+}
+
+func (n *Namespace) getIcingaState(namespace *kcorev1.Namespace) (IcingaState, string) {
+	if namespace.Status.Phase != kcorev1.NamespaceTerminating {
+		return Ok, fmt.Sprintf("Namespace %s is active.", n.Name)
+	}
+
+	for _, condition := range namespace.Status.Conditions {
+		if condition.Status != kcorev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case kcorev1.NamespaceDeletionDiscoveryFailure,
+			kcorev1.NamespaceDeletionGVParsingFailure,
+			kcorev1.NamespaceDeletionContentFailure:
+			return Critical, fmt.Sprintf(
+				"Namespace %s cannot be deleted. %s: %s.", n.Name, condition.Reason, condition.Message)
+		}
+	}
+
+	return Pending, fmt.Sprintf("Namespace %s is terminating.", n.Name)
+}
+
 func (n *Namespace) Relations() []database.Relation {
 	fk := database.WithForeignKey("namespace_uuid")
 
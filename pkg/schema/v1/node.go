@@ -208,15 +208,20 @@ func (n *Node) MarshalEvent() (notifications.Event, error) {
 		Severity: n.IcingaState.ToSeverity(),
 		Message:  n.IcingaStateReason,
 		URL:      &url.URL{Path: "/node", RawQuery: fmt.Sprintf("id=%s", n.Uuid)},
-		Tags: map[string]string{
-			"uuid":      n.Uuid.String(),
-			"name":      n.Name,
-			"namespace": n.Namespace,
-			"resource":  "node",
-		},
+		Tags:     n.eventTags("Node", "node"),
 	}, nil
 }
 
+// GetIcingaState implements the IcingaStater interface.
+func (n *Node) GetIcingaState() (IcingaState, string) {
+	return n.IcingaState, n.IcingaStateReason
+}
+
+// SetIcingaState implements the IcingaStater interface.
+func (n *Node) SetIcingaState(state IcingaState, reason string) {
+	n.IcingaState, n.IcingaStateReason = state, reason
+}
+
 func (n *Node) getIcingaState(node *kcorev1.Node) (IcingaState, string) {
 	// if node.Status.Phase == kcorev1.NodePending {
 	//	return Pending, fmt.Sprintf("Node %s is pending.", node.Name)
@@ -0,0 +1,238 @@
+package v1
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/icinga/icinga-go-library/types"
+	"github.com/icinga/icinga-kubernetes/pkg/database"
+	"github.com/icinga/icinga-kubernetes/pkg/notifications"
+	kcorev1 "k8s.io/api/core/v1"
+	kpolicyv1 "k8s.io/api/policy/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	kserializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	kjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PdbBlockingThreshold is the duration after which a PDB that does not allow any disruptions is considered
+// to block evictions, e.g. during node drains. Shorter periods without allowed disruptions are common,
+// for example during rollouts.
+const PdbBlockingThreshold = time.Hour
+
+type Pdb struct {
+	Meta
+	MinAvailable               sql.NullString
+	MaxUnavailable             sql.NullString
+	UnhealthyPodEvictionPolicy sql.NullString
+	CurrentHealthy             int32
+	DesiredHealthy             int32
+	DisruptionsAllowed         int32
+	ExpectedPods               int32
+	Yaml                       string
+	IcingaState                IcingaState
+	IcingaStateReason          string
+	Conditions                 []PdbCondition       `db:"-"`
+	PdbPods                    []PdbPod             `db:"-"`
+	Labels                     []Label              `db:"-"`
+	PdbLabels                  []PdbLabel           `db:"-"`
+	ResourceLabels             []ResourceLabel      `db:"-"`
+	Annotations                []Annotation         `db:"-"`
+	PdbAnnotations             []PdbAnnotation      `db:"-"`
+	ResourceAnnotations        []ResourceAnnotation `db:"-"`
+}
+
+type PdbCondition struct {
+	PdbUuid        types.UUID
+	Type           string
+	Status         string
+	LastTransition types.UnixMilli
+	Reason         string
+	Message        string
+}
+
+type PdbPod struct {
+	PdbUuid types.UUID
+	PodUuid types.UUID
+}
+
+type PdbLabel struct {
+	PdbUuid   types.UUID
+	LabelUuid types.UUID
+}
+
+type PdbAnnotation struct {
+	PdbUuid        types.UUID
+	AnnotationUuid types.UUID
+}
+
+func NewPdb() Resource {
+	return &Pdb{}
+}
+
+func (p *Pdb) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
+	p.ObtainMeta(k8s, clusterUuid)
+
+	pdb := k8s.(*kpolicyv1.PodDisruptionBudget)
+
+	p.MinAvailable = intOrStringToNullString(pdb.Spec.MinAvailable)
+	p.MaxUnavailable = intOrStringToNullString(pdb.Spec.MaxUnavailable)
+	if pdb.Spec.UnhealthyPodEvictionPolicy != nil {
+		p.UnhealthyPodEvictionPolicy = NewNullableString(string(*pdb.Spec.UnhealthyPodEvictionPolicy))
+	}
+	p.CurrentHealthy = pdb.Status.CurrentHealthy
+	p.DesiredHealthy = pdb.Status.DesiredHealthy
+	p.DisruptionsAllowed = pdb.Status.DisruptionsAllowed
+	p.ExpectedPods = pdb.Status.ExpectedPods
+
+	for _, condition := range pdb.Status.Conditions {
+		p.Conditions = append(p.Conditions, PdbCondition{
+			PdbUuid:        p.Uuid,
+			Type:           condition.Type,
+			Status:         string(condition.Status),
+			LastTransition: types.UnixMilli(condition.LastTransitionTime.Time),
+			Reason:         condition.Reason,
+			Message:        condition.Message,
+		})
+	}
+
+	p.IcingaState, p.IcingaStateReason = p.getIcingaState()
+
+	for labelName, labelValue := range pdb.Labels {
+		labelUuid := NewUUID(p.Uuid, strings.ToLower(labelName+":"+labelValue))
+		p.Labels = append(p.Labels, Label{
+			Uuid:  labelUuid,
+			Name:  labelName,
+			Value: labelValue,
+		})
+		p.PdbLabels = append(p.PdbLabels, PdbLabel{
+			PdbUuid:   p.Uuid,
+			LabelUuid: labelUuid,
+		})
+		p.ResourceLabels = append(p.ResourceLabels, ResourceLabel{
+			ResourceUuid: p.Uuid,
+			LabelUuid:    labelUuid,
+		})
+	}
+
+	for annotationName, annotationValue := range pdb.Annotations {
+		annotationUuid := NewUUID(p.Uuid, strings.ToLower(annotationName+":"+annotationValue))
+		p.Annotations = append(p.Annotations, Annotation{
+			Uuid:  annotationUuid,
+			Name:  annotationName,
+			Value: annotationValue,
+		})
+		p.PdbAnnotations = append(p.PdbAnnotations, PdbAnnotation{
+			PdbUuid:        p.Uuid,
+			AnnotationUuid: annotationUuid,
+		})
+		p.ResourceAnnotations = append(p.ResourceAnnotations, ResourceAnnotation{
+			ResourceUuid:   p.Uuid,
+			AnnotationUuid: annotationUuid,
+		})
+	}
+
+	scheme := kruntime.NewScheme()
+	_ = kpolicyv1.AddToScheme(scheme)
+	codec := kserializer.NewCodecFactory(scheme).EncoderForVersion(kjson.NewYAMLSerializer(kjson.DefaultMetaFactory, scheme, scheme), kpolicyv1.SchemeGroupVersion)
+	output, _ := kruntime.Encode(codec, pdb)
+	p.Yaml = string(output)
+}
+
+func (p *Pdb) MarshalEvent() (notifications.Event, error) {
+	return notifications.Event{
+		Name:     p.Namespace + "/" + p.Name,
+		Severity: p.IcingaState.ToSeverity(),
+		Message:  p.IcingaStateReason,
+		URL:      &url.URL{Path: "/pdb", RawQuery: fmt.Sprintf("id=%s", p.Uuid)},
+		Tags:     p.eventTags("PodDisruptionBudget", "pdb"),
+	}, nil
+}
+
+// GetIcingaState implements the IcingaStater interface.
+func (p *Pdb) GetIcingaState() (IcingaState, string) {
+	return p.IcingaState, p.IcingaStateReason
+}
+
+// SetIcingaState implements the IcingaStater interface.
+func (p *Pdb) SetIcingaState(state IcingaState, reason string) {
+	p.IcingaState, p.IcingaStateReason = state, reason
+}
+
+func (p *Pdb) getIcingaState() (IcingaState, string) {
+	var disruptionAllowed *PdbCondition
+	for i := range p.Conditions {
+		if p.Conditions[i].Type == kpolicyv1.DisruptionAllowedCondition {
+			disruptionAllowed = &p.Conditions[i]
+
+			break
+		}
+	}
+
+	if disruptionAllowed == nil {
+		reason := fmt.Sprintf("PDB %s/%s has not been evaluated yet.", p.Namespace, p.Name)
+
+		return Pending, reason
+	}
+
+	if disruptionAllowed.Reason == kpolicyv1.SyncFailedReason {
+		reason := fmt.Sprintf("PDB %s/%s cannot be evaluated: %s.", p.Namespace, p.Name, disruptionAllowed.Message)
+
+		return Unknown, reason
+	}
+
+	if p.ExpectedPods == 0 {
+		reason := fmt.Sprintf("PDB %s/%s does not select any pods.", p.Namespace, p.Name)
+
+		return Ok, reason
+	}
+
+	if p.DisruptionsAllowed > 0 || disruptionAllowed.Status != string(kcorev1.ConditionFalse) {
+		reason := fmt.Sprintf(
+			"PDB %s/%s allows %d disruptions with %d out of %d desired healthy pods.",
+			p.Namespace, p.Name, p.DisruptionsAllowed, p.CurrentHealthy, p.DesiredHealthy)
+
+		return Ok, reason
+	}
+
+	blockedSince := disruptionAllowed.LastTransition.Time()
+	if time.Since(blockedSince) < PdbBlockingThreshold {
+		reason := fmt.Sprintf(
+			"PDB %s/%s does not allow any disruptions since %s with %d out of %d desired healthy pods.",
+			p.Namespace, p.Name, blockedSince.Format(time.RFC3339), p.CurrentHealthy, p.DesiredHealthy)
+
+		return Ok, reason
+	}
+
+	reason := fmt.Sprintf(
+		"PDB %s/%s blocks evictions as it does not allow any disruptions since %s with %d out of %d desired healthy pods.",
+		p.Namespace, p.Name, blockedSince.Format(time.RFC3339), p.CurrentHealthy, p.DesiredHealthy)
+
+	return Warning, reason
+}
+
+func (p *Pdb) Relations() []database.Relation {
+	fk := database.WithForeignKey("pdb_uuid")
+
+	return []database.Relation{
+		database.HasMany(p.Conditions, fk),
+		database.HasMany(p.PdbPods, fk),
+		database.HasMany(p.ResourceLabels, database.WithForeignKey("resource_uuid")),
+		database.HasMany(p.Labels, database.WithoutCascadeDelete()),
+		database.HasMany(p.PdbLabels, fk),
+		database.HasMany(p.ResourceAnnotations, database.WithForeignKey("resource_uuid")),
+		database.HasMany(p.Annotations, database.WithoutCascadeDelete()),
+		database.HasMany(p.PdbAnnotations, fk),
+	}
+}
+
+func intOrStringToNullString(v *intstr.IntOrString) sql.NullString {
+	if v == nil {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: v.String(), Valid: true}
+}
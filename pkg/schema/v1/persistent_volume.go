@@ -2,14 +2,17 @@ package v1
 
 import (
 	"database/sql"
+	"fmt"
 	"github.com/icinga/icinga-go-library/types"
 	"github.com/icinga/icinga-kubernetes/pkg/database"
+	"github.com/icinga/icinga-kubernetes/pkg/notifications"
 	kcorev1 "k8s.io/api/core/v1"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kruntime "k8s.io/apimachinery/pkg/runtime"
 	kserializer "k8s.io/apimachinery/pkg/runtime/serializer"
 	kjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
 	ktypes "k8s.io/apimachinery/pkg/types"
+	"net/url"
 	"strings"
 )
 
@@ -26,6 +29,8 @@ type PersistentVolume struct {
 	Reason                      sql.NullString
 	Message                     sql.NullString
 	Yaml                        string
+	IcingaState                 IcingaState
+	IcingaStateReason           string
 	Claim                       *PersistentVolumeClaimRef    `db:"-"`
 	Labels                      []Label                      `db:"-"`
 	PersistentVolumeLabels      []PersistentVolumeLabel      `db:"-"`
@@ -91,6 +96,8 @@ func (p *PersistentVolume) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
 		}
 	}
 
+	p.IcingaState, p.IcingaStateReason = p.getIcingaState(persistentVolume)
+
 	for labelName, labelValue := range persistentVolume.Labels {
 		labelUuid := NewUUID(p.Uuid, strings.ToLower(labelName+":"+labelValue))
 		p.Labels = append(p.Labels, Label{
@@ -132,6 +139,71 @@ func (p *PersistentVolume) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
 	p.Yaml = string(output)
 }
 
+func (p *PersistentVolume) MarshalEvent() (notifications.Event, error) {
+	return notifications.Event{
+		Name:     p.Name,
+		Severity: p.IcingaState.ToSeverity(),
+		Message:  p.IcingaStateReason,
+		URL:      &url.URL{Path: "/persistentvolume", RawQuery: fmt.Sprintf("id=%s", p.Uuid)},
+		Tags:     p.eventTags("PersistentVolume", "persistent_volume"),
+	}, nil
+}
+
+// GetIcingaState implements the IcingaStater interface.
+func (p *PersistentVolume) GetIcingaState() (IcingaState, string) {
+	return p.IcingaState, p.IcingaStateReason
+}
+
+// SetIcingaState implements the IcingaStater interface.
+func (p *PersistentVolume) SetIcingaState(state IcingaState, reason string) {
+	p.IcingaState, p.IcingaStateReason = state, reason
+}
+
+func (p *PersistentVolume) getIcingaState(persistentVolume *kcorev1.PersistentVolume) (IcingaState, string) {
+	var claim string
+	if ref := persistentVolume.Spec.ClaimRef; ref != nil {
+		claim = ref.Namespace + "/" + ref.Name
+	}
+
+	switch persistentVolume.Status.Phase {
+	case kcorev1.VolumePending:
+		reason := fmt.Sprintf("Persistent volume %s is pending.", p.Name)
+
+		return Pending, reason
+	case kcorev1.VolumeAvailable:
+		reason := fmt.Sprintf("Persistent volume %s is available.", p.Name)
+
+		return Ok, reason
+	case kcorev1.VolumeBound:
+		reason := fmt.Sprintf("Persistent volume %s is bound to claim %s.", p.Name, claim)
+
+		return Ok, reason
+	case kcorev1.VolumeReleased:
+		// Retained volumes are reclaimed manually on purpose, so being released is their expected end state.
+		if persistentVolume.Spec.PersistentVolumeReclaimPolicy == kcorev1.PersistentVolumeReclaimRetain {
+			reason := fmt.Sprintf(
+				"Persistent volume %s has been released from claim %s and is retained for manual reclamation.",
+				p.Name, claim)
+
+			return Ok, reason
+		}
+
+		reason := fmt.Sprintf(
+			"Persistent volume %s has been released from claim %s but not yet reclaimed with reclaim policy %s.",
+			p.Name, claim, persistentVolume.Spec.PersistentVolumeReclaimPolicy)
+
+		return Warning, reason
+	case kcorev1.VolumeFailed:
+		reason := fmt.Sprintf("Persistent volume %s failed to be reclaimed: %s.", p.Name, persistentVolume.Status.Message)
+
+		return Critical, reason
+	default:
+		reason := fmt.Sprintf("Persistent volume %s has unknown phase %q.", p.Name, persistentVolume.Status.Phase)
+
+		return Unknown, reason
+	}
+}
+
 func (p *PersistentVolume) Relations() []database.Relation {
 	if p.Claim == nil {
 		return []database.Relation{}
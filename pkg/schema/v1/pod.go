@@ -294,15 +294,20 @@ func (p *Pod) MarshalEvent() (notifications.Event, error) {
 		Severity: p.IcingaState.ToSeverity(),
 		Message:  p.IcingaStateReason,
 		URL:      &url.URL{Path: "/pod", RawQuery: fmt.Sprintf("id=%s", p.Uuid)},
-		Tags: map[string]string{
-			"uuid":      p.Uuid.String(),
-			"name":      p.Name,
-			"namespace": p.Namespace,
-			"resource":  "pod",
-		},
+		Tags:     p.eventTags("Pod", "pod"),
 	}, nil
 }
 
+// GetIcingaState implements the IcingaStater interface.
+func (p *Pod) GetIcingaState() (IcingaState, string) {
+	return p.IcingaState, p.IcingaStateReason
+}
+
+// SetIcingaState implements the IcingaStater interface.
+func (p *Pod) SetIcingaState(state IcingaState, reason string) {
+	p.IcingaState, p.IcingaStateReason = state, reason
+}
+
 func (p *Pod) getIcingaState(pod *kcorev1.Pod) (IcingaState, string) {
 	if pod.Status.Reason == "NodeLost" {
 		return Unknown, fmt.Sprintf(
@@ -2,17 +2,30 @@ package v1
 
 import (
 	"database/sql"
+	"fmt"
 	"github.com/icinga/icinga-go-library/strcase"
 	"github.com/icinga/icinga-go-library/types"
 	"github.com/icinga/icinga-kubernetes/pkg/database"
+	"github.com/icinga/icinga-kubernetes/pkg/notifications"
 	kcorev1 "k8s.io/api/core/v1"
+	kstoragev1 "k8s.io/api/storage/v1"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kruntime "k8s.io/apimachinery/pkg/runtime"
 	kserializer "k8s.io/apimachinery/pkg/runtime/serializer"
 	kjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	kstoragelistersv1 "k8s.io/client-go/listers/storage/v1"
+	"net/url"
 	"strings"
+	"time"
 )
 
+// PvcPendingThreshold is the duration after which a PVC that is still pending is considered stuck.
+const PvcPendingThreshold = 5 * time.Minute
+
+// pvcSelectedNodeAnnotation is set by the scheduler on PVCs whose storage class waits for the first consumer
+// once a consuming pod has been scheduled, which triggers the provisioning of a volume on the selected node.
+const pvcSelectedNodeAnnotation = "volume.kubernetes.io/selected-node"
+
 type kpersistentVolumeAccessModesSize byte
 
 type kpersistentVolumeAccessModes map[kcorev1.PersistentVolumeAccessMode]kpersistentVolumeAccessModesSize
@@ -34,6 +47,10 @@ var persistentVolumeAccessModes = kpersistentVolumeAccessModes{
 	kcorev1.ReadWriteOncePod: 1 << 3,
 }
 
+type PvcFactory struct {
+	storageClasses kstoragelistersv1.StorageClassLister
+}
+
 type Pvc struct {
 	Meta
 	DesiredAccessModes  Bitmask[kpersistentVolumeAccessModesSize]
@@ -45,6 +62,8 @@ type Pvc struct {
 	VolumeMode          string
 	StorageClass        sql.NullString
 	Yaml                string
+	IcingaState         IcingaState
+	IcingaStateReason   string
 	Conditions          []PvcCondition       `db:"-"`
 	Labels              []Label              `db:"-"`
 	PvcLabels           []PvcLabel           `db:"-"`
@@ -52,6 +71,7 @@ type Pvc struct {
 	Annotations         []Annotation         `db:"-"`
 	PvcAnnotations      []PvcAnnotation      `db:"-"`
 	ResourceAnnotations []ResourceAnnotation `db:"-"`
+	factory             *PvcFactory
 }
 
 type PvcCondition struct {
@@ -74,8 +94,16 @@ type PvcAnnotation struct {
 	AnnotationUuid types.UUID
 }
 
-func NewPvc() Resource {
-	return &Pvc{}
+// NewPvcFactory returns a new PvcFactory that looks up the storage classes of PVCs via the given lister,
+// which are taken into account for the Icinga state of the PVCs.
+func NewPvcFactory(storageClasses kstoragelistersv1.StorageClassLister) *PvcFactory {
+	return &PvcFactory{
+		storageClasses: storageClasses,
+	}
+}
+
+func (f *PvcFactory) NewPvc() Resource {
+	return &Pvc{factory: f}
 }
 
 func (p *Pvc) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
@@ -120,6 +148,8 @@ func (p *Pvc) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
 		})
 	}
 
+	p.IcingaState, p.IcingaStateReason = p.getIcingaState(pvc)
+
 	for labelName, labelValue := range pvc.Labels {
 		labelUuid := NewUUID(p.Uuid, strings.ToLower(labelName+":"+labelValue))
 		p.Labels = append(p.Labels, Label{
@@ -161,6 +191,116 @@ func (p *Pvc) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
 	p.Yaml = string(output)
 }
 
+func (p *Pvc) MarshalEvent() (notifications.Event, error) {
+	return notifications.Event{
+		Name:     p.Namespace + "/" + p.Name,
+		Severity: p.IcingaState.ToSeverity(),
+		Message:  p.IcingaStateReason,
+		URL:      &url.URL{Path: "/pvc", RawQuery: fmt.Sprintf("id=%s", p.Uuid)},
+		Tags:     p.eventTags("PersistentVolumeClaim", "pvc"),
+	}, nil
+}
+
+// GetIcingaState implements the IcingaStater interface.
+func (p *Pvc) GetIcingaState() (IcingaState, string) {
+	return p.IcingaState, p.IcingaStateReason
+}
+
+// SetIcingaState implements the IcingaStater interface.
+func (p *Pvc) SetIcingaState(state IcingaState, reason string) {
+	p.IcingaState, p.IcingaStateReason = state, reason
+}
+
+// waitsForFirstConsumer returns whether the given pending PVC is not bound on purpose,
+// because its storage class binds volumes only once a consuming pod has been scheduled.
+func (p *Pvc) waitsForFirstConsumer(pvc *kcorev1.PersistentVolumeClaim) bool {
+	if _, ok := pvc.Annotations[pvcSelectedNodeAnnotation]; ok {
+		// A consumer has been scheduled, so the PVC is expected to be bound now.
+		return false
+	}
+
+	if p.factory == nil || pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return false
+	}
+
+	storageClass, err := p.factory.storageClasses.Get(*pvc.Spec.StorageClassName)
+	if err != nil {
+		return false
+	}
+
+	return storageClass.VolumeBindingMode != nil &&
+		*storageClass.VolumeBindingMode == kstoragev1.VolumeBindingWaitForFirstConsumer
+}
+
+func (p *Pvc) getIcingaState(pvc *kcorev1.PersistentVolumeClaim) (IcingaState, string) {
+	switch pvc.Status.Phase {
+	case kcorev1.ClaimLost:
+		reason := fmt.Sprintf("PVC %s/%s lost its underlying volume %s.", p.Namespace, p.Name, pvc.Spec.VolumeName)
+
+		return Critical, reason
+	case kcorev1.ClaimPending:
+		if p.waitsForFirstConsumer(pvc) {
+			reason := fmt.Sprintf(
+				"PVC %s/%s is waiting for its first consumer to be scheduled before it is bound to a volume.",
+				p.Namespace, p.Name)
+
+			return Ok, reason
+		}
+
+		pending := time.Since(pvc.CreationTimestamp.Time)
+		if pending < PvcPendingThreshold {
+			reason := fmt.Sprintf("PVC %s/%s is pending.", p.Namespace, p.Name)
+
+			return Pending, reason
+		}
+
+		reason := fmt.Sprintf(
+			"PVC %s/%s is stuck in pending for %s and is not bound to a volume.",
+			p.Namespace, p.Name, pending.Truncate(time.Minute))
+
+		return Warning, reason
+	}
+
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Status != kcorev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case kcorev1.PersistentVolumeClaimControllerResizeError, kcorev1.PersistentVolumeClaimNodeResizeError:
+			reason := fmt.Sprintf("PVC %s/%s cannot be resized: %s.", p.Namespace, p.Name, condition.Message)
+
+			return Critical, reason
+		case kcorev1.PersistentVolumeClaimFileSystemResizePending:
+			reason := fmt.Sprintf(
+				"PVC %s/%s is waiting for a pod to (re)start to finish the file system resize.", p.Namespace, p.Name)
+
+			return Warning, reason
+		}
+	}
+
+	switch pvc.Status.AllocatedResourceStatuses[kcorev1.ResourceStorage] {
+	case kcorev1.PersistentVolumeClaimControllerResizeInfeasible, kcorev1.PersistentVolumeClaimNodeResizeInfeasible:
+		reason := fmt.Sprintf(
+			"PVC %s/%s cannot be resized as the requested size is not supported by the storage provider.",
+			p.Namespace, p.Name)
+
+		return Critical, reason
+	case kcorev1.PersistentVolumeClaimNodeResizePending:
+		reason := fmt.Sprintf("PVC %s/%s is waiting for the node to finish the resize.", p.Namespace, p.Name)
+
+		return Warning, reason
+	case kcorev1.PersistentVolumeClaimControllerResizeInProgress, kcorev1.PersistentVolumeClaimNodeResizeInProgress:
+		reason := fmt.Sprintf("PVC %s/%s is being resized.", p.Namespace, p.Name)
+
+		return Ok, reason
+	}
+
+	reason := fmt.Sprintf("PVC %s/%s is bound to volume %s.", p.Namespace, p.Name, pvc.Spec.VolumeName)
+
+	return Ok, reason
+}
+
 func (p *Pvc) Relations() []database.Relation {
 	fk := database.WithForeignKey("pvc_uuid")
 
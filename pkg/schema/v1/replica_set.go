@@ -171,15 +171,20 @@ func (r *ReplicaSet) MarshalEvent() (notifications.Event, error) {
 		Severity: r.IcingaState.ToSeverity(),
 		Message:  r.IcingaStateReason,
 		URL:      &url.URL{Path: "/replicaset", RawQuery: fmt.Sprintf("id=%s", r.Uuid)},
-		Tags: map[string]string{
-			"uuid":      r.Uuid.String(),
-			"name":      r.Name,
-			"namespace": r.Namespace,
-			"resource":  "replica_set",
-		},
+		Tags:     r.eventTags("ReplicaSet", "replica_set"),
 	}, nil
 }
 
+// GetIcingaState implements the IcingaStater interface.
+func (r *ReplicaSet) GetIcingaState() (IcingaState, string) {
+	return r.IcingaState, r.IcingaStateReason
+}
+
+// SetIcingaState implements the IcingaStater interface.
+func (r *ReplicaSet) SetIcingaState(state IcingaState, reason string) {
+	r.IcingaState, r.IcingaStateReason = state, reason
+}
+
 func (r *ReplicaSet) getIcingaState() (IcingaState, string) {
 	for _, condition := range r.Conditions {
 		if condition.Type == string(kappsv1.ReplicaSetReplicaFailure) && condition.Status == string(kcorev1.ConditionTrue) {
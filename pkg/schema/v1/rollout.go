@@ -0,0 +1,128 @@
+package v1
+
+import (
+	"database/sql"
+	"github.com/icinga/icinga-go-library/types"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+// Rollout outcomes.
+const (
+	// RolloutProgressing is the outcome of rollouts that have not ended yet.
+	RolloutProgressing = "progressing"
+
+	// RolloutStalled is the outcome of rollouts that have not ended yet and have not made progress in time.
+	RolloutStalled = "stalled"
+
+	// RolloutComplete is the outcome of rollouts whose workloads have converged to their desired state.
+	RolloutComplete = "complete"
+
+	// RolloutSuperseded is the outcome of rollouts that have been replaced by a rollout of a newer revision.
+	RolloutSuperseded = "superseded"
+
+	// RolloutDeleted is the outcome of rollouts whose workloads have been deleted before they completed.
+	RolloutDeleted = "deleted"
+)
+
+// rolloutProgressDeadline is how long the rollout of a StatefulSet or DaemonSet may not make any progress
+// until it is considered stalled. As these resources have no progress deadline of their own,
+// the default of Deployments is used.
+const rolloutProgressDeadline = 600 * time.Second
+
+// RolloutStatus is the status of the rollout of a workload as observed in its Kubernetes object,
+// which is determined the same way as kubectl rollout status does.
+type RolloutStatus struct {
+	// Generation is the generation of the workload spec that is rolled out.
+	Generation int64
+
+	// Revision identifies the pod template of the workload. Only a change of it starts a rollout.
+	Revision string
+
+	// Updating is true if replicas have yet to be updated to the revision, which starts a rollout.
+	Updating bool
+
+	// Progressing is true if the workload has not converged to its desired state yet,
+	// i.e. it is updating or its updated replicas are not available yet.
+	Progressing bool
+
+	// Stalled is true if the rollout has not made progress in time.
+	Stalled bool
+
+	// Message describes the rollout if it is progressing.
+	Message string
+}
+
+// Outcome returns RolloutStalled or RolloutProgressing depending on whether the rollout is stalled.
+func (s RolloutStatus) Outcome() string {
+	if s.Stalled {
+		return RolloutStalled
+	}
+
+	return RolloutProgressing
+}
+
+// eventTags adds the outcome of an updating rollout to the given tags of notification events.
+func (s RolloutStatus) eventTags(tags map[string]string) map[string]string {
+	if s.Updating {
+		tags["rollout"] = s.Outcome()
+	}
+
+	return tags
+}
+
+// Rollout is a rollout of a workload, i.e. a Deployment, StatefulSet or DaemonSet,
+// from its start until it has converged to its desired state.
+type Rollout struct {
+	Uuid         types.UUID
+	ClusterUuid  types.UUID
+	ResourceUuid types.UUID
+	Kind         string
+	Namespace    string
+	Name         string
+	Generation   int64
+	StartTime    types.UnixMilli
+	EndTime      types.UnixMilli
+	Duration     sql.NullInt64
+	Outcome      string
+	Message      string
+}
+
+// End ends the rollout at the given time with the given outcome.
+func (r *Rollout) End(end time.Time, outcome string) {
+	r.EndTime = types.UnixMilli(end)
+	r.Duration = sql.NullInt64{Int64: end.Sub(r.StartTime.Time()).Milliseconds(), Valid: true}
+	r.Outcome = outcome
+}
+
+// Rolling is implemented by workloads whose rollouts are tracked.
+type Rolling interface {
+	Resource
+
+	// GetRolloutStatus returns the status of the rollout of the workload.
+	GetRolloutStatus() RolloutStatus
+
+	// AddRollout adds the given rollout, which is persisted together with the workload.
+	AddRollout(r Rollout)
+}
+
+// lastStatusUpdate returns when the status of the given object was last updated according to its managed fields,
+// which is the case whenever the rollout of a workload makes progress. If unknown, the zero time is returned.
+func lastStatusUpdate(k8s kmetav1.Object) time.Time {
+	var last time.Time
+	for _, entry := range k8s.GetManagedFields() {
+		if entry.Subresource == "status" && entry.Time != nil && entry.Time.After(last) {
+			last = entry.Time.Time
+		}
+	}
+
+	return last
+}
+
+// stalledSince returns when the status of the given object was last updated and whether this is longer ago than
+// the rollout progress deadline, i.e. whether its rollout is stalled.
+func stalledSince(k8s kmetav1.Object) (time.Time, bool) {
+	last := lastStatusUpdate(k8s)
+
+	return last, !last.IsZero() && time.Since(last) >= rolloutProgressDeadline
+}
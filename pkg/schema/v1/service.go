@@ -2,20 +2,27 @@ package v1
 
 import (
 	"database/sql"
+	"fmt"
 	"github.com/icinga/icinga-go-library/strcase"
 	"github.com/icinga/icinga-go-library/types"
 	"github.com/icinga/icinga-kubernetes/pkg/database"
+	"github.com/icinga/icinga-kubernetes/pkg/notifications"
 	kcorev1 "k8s.io/api/core/v1"
+	kdiscoveryv1 "k8s.io/api/discovery/v1"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	kruntime "k8s.io/apimachinery/pkg/runtime"
 	kserializer "k8s.io/apimachinery/pkg/runtime/serializer"
 	kjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
 	"k8s.io/client-go/kubernetes"
+	kdiscoverylistersv1 "k8s.io/client-go/listers/discovery/v1"
+	"net/url"
 	"strings"
 )
 
 type ServiceFactory struct {
-	clientset *kubernetes.Clientset
+	clientset      *kubernetes.Clientset
+	endpointSlices kdiscoverylistersv1.EndpointSliceLister
 }
 
 type Service struct {
@@ -35,6 +42,8 @@ type Service struct {
 	LoadBalancerClass             sql.NullString
 	InternalTrafficPolicy         string
 	Yaml                          string
+	IcingaState                   IcingaState
+	IcingaStateReason             string
 	Selectors                     []Selector           `db:"-"`
 	ServiceSelectors              []ServiceSelector    `db:"-"`
 	Ports                         []ServicePort        `db:"-"`
@@ -89,9 +98,14 @@ type ServicePod struct {
 	PodUuid     types.UUID
 }
 
-func NewServiceFactory(clientset *kubernetes.Clientset) *ServiceFactory {
+// NewServiceFactory returns a new ServiceFactory that looks up the endpoints of services via the given lister,
+// which are taken into account for the Icinga state of the services.
+func NewServiceFactory(
+	clientset *kubernetes.Clientset, endpointSlices kdiscoverylistersv1.EndpointSliceLister,
+) *ServiceFactory {
 	return &ServiceFactory{
-		clientset: clientset,
+		clientset:      clientset,
+		endpointSlices: endpointSlices,
 	}
 }
 
@@ -234,6 +248,8 @@ func (s *Service) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
 		internalTrafficPolicy = string(*service.Spec.InternalTrafficPolicy)
 	}
 	s.InternalTrafficPolicy = internalTrafficPolicy
+	s.IcingaState, s.IcingaStateReason = s.getIcingaState(service)
+
 	scheme := kruntime.NewScheme()
 	_ = kcorev1.AddToScheme(scheme)
 	codec := kserializer.NewCodecFactory(scheme).EncoderForVersion(kjson.NewYAMLSerializer(kjson.DefaultMetaFactory, scheme, scheme), kcorev1.SchemeGroupVersion)
@@ -241,6 +257,65 @@ func (s *Service) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
 	s.Yaml = string(output)
 }
 
+func (s *Service) MarshalEvent() (notifications.Event, error) {
+	return notifications.Event{
+		Name:     s.Namespace + "/" + s.Name,
+		Severity: s.IcingaState.ToSeverity(),
+		Message:  s.IcingaStateReason,
+		URL:      &url.URL{Path: "/service", RawQuery: fmt.Sprintf("id=%s", s.Uuid)},
+		Tags:     s.eventTags("Service", "service"),
+	}, nil
+}
+
+// GetIcingaState implements the IcingaStater interface.
+func (s *Service) GetIcingaState() (IcingaState, string) {
+	return s.IcingaState, s.IcingaStateReason
+}
+
+// SetIcingaState implements the IcingaStater interface.
+func (s *Service) SetIcingaState(state IcingaState, reason string) {
+	s.IcingaState, s.IcingaStateReason = state, reason
+}
+
+func (s *Service) getIcingaState(service *kcorev1.Service) (IcingaState, string) {
+	if service.Spec.Type == kcorev1.ServiceTypeExternalName {
+		reason := fmt.Sprintf("Service %s/%s is an alias for %s.", s.Namespace, s.Name, service.Spec.ExternalName)
+
+		return Ok, reason
+	}
+
+	if service.Spec.Type == kcorev1.ServiceTypeLoadBalancer && len(service.Status.LoadBalancer.Ingress) == 0 {
+		reason := fmt.Sprintf("Service %s/%s has not been assigned a load balancer ingress IP.", s.Namespace, s.Name)
+
+		return Warning, reason
+	}
+
+	// Services without selectors have their endpoints managed manually or by third parties,
+	// so there are no pods to expect.
+	if len(service.Spec.Selector) == 0 || s.factory == nil {
+		reason := fmt.Sprintf("Service %s/%s is ok.", s.Namespace, s.Name)
+
+		return Ok, reason
+	}
+
+	ready, err := readyEndpoints(s.factory.endpointSlices, s.Namespace, s.Name)
+	if err != nil {
+		reason := fmt.Sprintf("Service %s/%s cannot be evaluated: %s.", s.Namespace, s.Name, err)
+
+		return Unknown, reason
+	}
+
+	if ready == 0 {
+		reason := fmt.Sprintf("Service %s/%s does not have any ready pods.", s.Namespace, s.Name)
+
+		return Critical, reason
+	}
+
+	reason := fmt.Sprintf("Service %s/%s has %d ready endpoints.", s.Namespace, s.Name, ready)
+
+	return Ok, reason
+}
+
 func (s *Service) Relations() []database.Relation {
 	fk := database.WithForeignKey("service_uuid")
 
@@ -259,3 +334,32 @@ func (s *Service) Relations() []database.Relation {
 		database.HasMany(s.ServicePods, fk),
 	}
 }
+
+// readyEndpoints returns the number of ready endpoints of the given service according to its endpoint slices.
+// Endpoints that appear in multiple slices, e.g. for different address families, are counted once.
+func readyEndpoints(endpointSlices kdiscoverylistersv1.EndpointSliceLister, namespace, service string) (int, error) {
+	slices, err := endpointSlices.EndpointSlices(namespace).List(
+		labels.SelectorFromSet(labels.Set{kdiscoveryv1.LabelServiceName: service}))
+	if err != nil {
+		return 0, err
+	}
+
+	ready := make(map[string]struct{})
+	for _, slice := range slices {
+		for _, endpoint := range slice.Endpoints {
+			// A nil ready condition is to be interpreted as ready.
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				continue
+			}
+
+			switch {
+			case endpoint.TargetRef != nil:
+				ready[string(endpoint.TargetRef.UID)] = struct{}{}
+			case len(endpoint.Addresses) > 0:
+				ready[endpoint.Addresses[0]] = struct{}{}
+			}
+		}
+	}
+
+	return len(ready), nil
+}
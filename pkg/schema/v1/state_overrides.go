@@ -0,0 +1,118 @@
+package v1
+
+import (
+	"fmt"
+	kappsv1 "k8s.io/api/apps/v1"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strconv"
+	"strings"
+)
+
+const (
+	// IgnoreAnnotation sets the Icinga state of an object to ok if set to true, i.e. ignores its problems.
+	IgnoreAnnotation = "icinga.com/ignore"
+
+	// MinAvailableAnnotation sets the number of available replicas a workload requires to be ok.
+	MinAvailableAnnotation = "icinga.com/min-available"
+
+	// SeverityCapAnnotation sets the worst Icinga state of an object, e.g. warning.
+	SeverityCapAnnotation = "icinga.com/severity-cap"
+)
+
+// stateOverrides are adjustments of the Icinga state of an object by its owners, defined by its annotations.
+type stateOverrides struct {
+	ignore          bool
+	minAvailable    int32
+	minAvailableSet bool
+	severityCap     IcingaState
+	severityCapSet  bool
+
+	// invalid describes the annotations with invalid values, which are ignored.
+	invalid []string
+}
+
+// parseStateOverrides returns the state overrides defined by the given annotations of an object.
+func parseStateOverrides(annotations map[string]string) stateOverrides {
+	var o stateOverrides
+
+	if v, ok := annotations[IgnoreAnnotation]; ok {
+		ignore, err := strconv.ParseBool(v)
+		if err != nil {
+			o.invalid = append(o.invalid, fmt.Sprintf("%s=%q", IgnoreAnnotation, v))
+		} else {
+			o.ignore = ignore
+		}
+	}
+
+	if v, ok := annotations[MinAvailableAnnotation]; ok {
+		minAvailable, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || minAvailable < 0 {
+			o.invalid = append(o.invalid, fmt.Sprintf("%s=%q", MinAvailableAnnotation, v))
+		} else {
+			o.minAvailable = int32(minAvailable)
+			o.minAvailableSet = true
+		}
+	}
+
+	if v, ok := annotations[SeverityCapAnnotation]; ok {
+		severityCap, err := ParseIcingaState(v)
+		if err != nil {
+			o.invalid = append(o.invalid, fmt.Sprintf("%s=%q", SeverityCapAnnotation, v))
+		} else {
+			o.severityCap = severityCap
+			o.severityCapSet = true
+		}
+	}
+
+	return o
+}
+
+// ApplyStateOverrides applies the ignore and severity cap annotations of the given Kubernetes object to the state of
+// the given entity, which has been obtained from the object. It must be called after all other adjustments
+// of the state, i.e. metric thresholds and state rules, so that the annotations have the final say.
+func ApplyStateOverrides(k8s kmetav1.Object, entity IcingaStater) {
+	switch k8s.(type) {
+	case *kappsv1.Deployment, *kappsv1.StatefulSet, *kappsv1.DaemonSet, *kcorev1.Pod:
+	default:
+		return
+	}
+
+	entity.SetIcingaState(parseStateOverrides(k8s.GetAnnotations()).apply(entity.GetIcingaState()))
+}
+
+// availability returns the Icinga state of a workload of the given kind with the given number of available replicas
+// and true if the minimum number of available replicas is overridden. Otherwise, it returns false.
+func (o stateOverrides) availability(kind, namespace, name string, available int32) (IcingaState, string, bool) {
+	if !o.minAvailableSet {
+		return 0, "", false
+	}
+
+	if available < o.minAvailable {
+		return Critical, fmt.Sprintf(
+			"%s %s/%s only has %d available replicas, but at least %d are required.",
+			kind, namespace, name, available, o.minAvailable), true
+	}
+
+	return Ok, fmt.Sprintf(
+		"%s %s/%s has %d available replicas, of which at least %d are required.",
+		kind, namespace, name, available, o.minAvailable), true
+}
+
+// apply returns the given state capped at the severity cap, or ok if the object is ignored,
+// and the given reason extended accordingly.
+func (o stateOverrides) apply(state IcingaState, reason string) (IcingaState, string) {
+	switch {
+	case o.ignore && state != Ok:
+		state, reason = Ok, fmt.Sprintf("Problems are ignored via the %s annotation. %s", IgnoreAnnotation, reason)
+	case o.severityCapSet && state > o.severityCap:
+		state, reason = o.severityCap, fmt.Sprintf(
+			"%s State %s is capped at %s via the %s annotation.", reason, state, o.severityCap, SeverityCapAnnotation)
+	}
+
+	if len(o.invalid) > 0 {
+		reason = fmt.Sprintf("%s Ignoring invalid annotations: %s.", reason, strings.Join(o.invalid, ", "))
+	}
+
+	return state, reason
+}
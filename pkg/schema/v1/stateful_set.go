@@ -14,6 +14,7 @@ import (
 	ktypes "k8s.io/apimachinery/pkg/types"
 	"net/url"
 	"strings"
+	"time"
 )
 
 type StatefulSet struct {
@@ -42,6 +43,10 @@ type StatefulSet struct {
 	Annotations                                     []Annotation            `db:"-"`
 	StatefulSetAnnotations                          []StatefulSetAnnotation `db:"-"`
 	ResourceAnnotations                             []ResourceAnnotation    `db:"-"`
+	Rollouts                                        []Rollout               `db:"-"`
+
+	// rollout is the status of the rollout of the stateful set.
+	rollout RolloutStatus
 }
 
 type StatefulSetCondition struct {
@@ -111,7 +116,8 @@ func (s *StatefulSet) Obtain(k8s kmetav1.Object, clusterUuid types.UUID) {
 	s.CurrentReplicas = statefulSet.Status.CurrentReplicas
 	s.UpdatedReplicas = statefulSet.Status.UpdatedReplicas
 	s.AvailableReplicas = statefulSet.Status.AvailableReplicas
-	s.IcingaState, s.IcingaStateReason = s.getIcingaState()
+	s.rollout = s.getRolloutStatus(statefulSet)
+	s.IcingaState, s.IcingaStateReason = s.getIcingaState(parseStateOverrides(statefulSet.Annotations))
 
 	for _, condition := range statefulSet.Status.Conditions {
 		s.Conditions = append(s.Conditions, StatefulSetCondition{
@@ -196,16 +202,41 @@ func (s *StatefulSet) MarshalEvent() (notifications.Event, error) {
 		Severity: s.IcingaState.ToSeverity(),
 		Message:  s.IcingaStateReason,
 		URL:      &url.URL{Path: "/statefulset", RawQuery: fmt.Sprintf("id=%s", s.Uuid)},
-		Tags: map[string]string{
-			"uuid":      s.Uuid.String(),
-			"name":      s.Name,
-			"namespace": s.Namespace,
-			"resource":  "stateful_set",
-		},
+		Tags:     s.rollout.eventTags(s.eventTags("StatefulSet", "stateful_set")),
 	}, nil
 }
 
-func (s *StatefulSet) getIcingaState() (IcingaState, string) {
+// GetIcingaState implements the IcingaStater interface.
+func (s *StatefulSet) GetIcingaState() (IcingaState, string) {
+	return s.IcingaState, s.IcingaStateReason
+}
+
+// SetIcingaState implements the IcingaStater interface.
+func (s *StatefulSet) SetIcingaState(state IcingaState, reason string) {
+	s.IcingaState, s.IcingaStateReason = state, reason
+}
+
+// GetRolloutStatus implements the Rolling interface.
+func (s *StatefulSet) GetRolloutStatus() RolloutStatus {
+	return s.rollout
+}
+
+// AddRollout implements the Rolling interface.
+func (s *StatefulSet) AddRollout(r Rollout) {
+	s.Rollouts = append(s.Rollouts, r)
+}
+
+func (s *StatefulSet) getIcingaState(overrides stateOverrides) (IcingaState, string) {
+	if s.rollout.Stalled {
+		return Critical, s.rollout.Message
+	}
+
+	// Problems other than the number of available replicas are critical and thus never better than
+	// the availability required via annotation, which only replaces the evaluation of the replica counts.
+	if state, reason, ok := overrides.availability("StatefulSet", s.Namespace, s.Name, s.AvailableReplicas); ok {
+		return state, reason
+	}
+
 	switch {
 	case s.AvailableReplicas == 0:
 		reason := fmt.Sprintf("StatefulSet %s/%s has no replica available from %d desired.", s.Namespace, s.Name, s.DesiredReplicas)
@@ -222,6 +253,49 @@ func (s *StatefulSet) getIcingaState() (IcingaState, string) {
 	}
 }
 
+func (s *StatefulSet) getRolloutStatus(statefulSet *kappsv1.StatefulSet) RolloutStatus {
+	// The update revision only changes if the pod template changes.
+	revision := statefulSet.Status.UpdateRevision
+
+	// Pods of stateful sets with the OnDelete strategy are only updated when they are deleted manually.
+	if statefulSet.Spec.UpdateStrategy.Type != kappsv1.RollingUpdateStatefulSetStrategyType {
+		return RolloutStatus{Generation: statefulSet.Generation, Revision: revision}
+	}
+
+	var partition int32
+	if rollingUpdate := statefulSet.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.Partition != nil {
+		partition = *rollingUpdate.Partition
+	}
+
+	status := RolloutStatus{Generation: statefulSet.Generation, Revision: revision, Updating: true, Progressing: true}
+	updating := statefulSet.Status.UpdateRevision != statefulSet.Status.CurrentRevision
+
+	switch {
+	case statefulSet.Status.ObservedGeneration < statefulSet.Generation:
+		// A new generation may just scale the stateful set, which is not a rollout.
+		status.Updating = false
+		status.Message = fmt.Sprintf("StatefulSet %s/%s is waiting for generation %d to be observed.", s.Namespace, s.Name, statefulSet.Generation)
+	case updating && partition > 0 && s.UpdatedReplicas < s.DesiredReplicas-partition:
+		status.Message = fmt.Sprintf("StatefulSet %s/%s is rolling out: %d out of %d new replicas above partition %d have been updated.", s.Namespace, s.Name, s.UpdatedReplicas, s.DesiredReplicas-partition, partition)
+	case updating && partition == 0:
+		status.Message = fmt.Sprintf("StatefulSet %s/%s is rolling out: %d out of %d new replicas have been updated.", s.Namespace, s.Name, s.UpdatedReplicas, s.DesiredReplicas)
+	case s.ReadyReplicas < s.DesiredReplicas:
+		status.Updating = false
+		status.Message = fmt.Sprintf("StatefulSet %s/%s is rolling out: %d out of %d replicas are ready.", s.Namespace, s.Name, s.ReadyReplicas, s.DesiredReplicas)
+	default:
+		return RolloutStatus{Generation: statefulSet.Generation, Revision: revision}
+	}
+
+	if status.Updating {
+		if since, stalled := stalledSince(statefulSet); stalled {
+			status.Stalled = true
+			status.Message = fmt.Sprintf("%s No progress has been made since %s.", status.Message, since.Format(time.RFC3339))
+		}
+	}
+
+	return status
+}
+
 func (s *StatefulSet) Relations() []database.Relation {
 	fk := database.WithForeignKey("stateful_set_uuid")
 
@@ -234,5 +308,6 @@ func (s *StatefulSet) Relations() []database.Relation {
 		database.HasMany(s.ResourceAnnotations, database.WithForeignKey("resource_uuid")),
 		database.HasMany(s.Annotations, database.WithoutCascadeDelete()),
 		database.HasMany(s.StatefulSetAnnotations, fk),
+		database.HasMany(s.Rollouts, database.WithForeignKey("resource_uuid"), database.WithoutCascadeDelete()),
 	}
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"github.com/go-logr/logr"
+	schemav1 "github.com/icinga/icinga-kubernetes/pkg/schema/v1"
+	"github.com/icinga/icinga-kubernetes/pkg/telemetry"
 	"github.com/pkg/errors"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
@@ -13,20 +15,26 @@ import (
 
 type Controller struct {
 	informer cache.SharedIndexInformer
+	name     string
 	log      logr.Logger
 	queue    workqueue.TypedRateLimitingInterface[EventHandlerItem]
 }
 
+// NewController creates a new Controller for the given informer.
+// The name identifies the controller in metrics and health checks.
 func NewController(
 	informer cache.SharedIndexInformer,
+	name string,
 	log logr.Logger,
 ) *Controller {
 
 	return &Controller{
 		informer: informer,
+		name:     name,
 		log:      log,
-		queue: workqueue.NewTypedRateLimitingQueue[EventHandlerItem](
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig[EventHandlerItem](
 			workqueue.DefaultTypedControllerRateLimiter[EventHandlerItem](),
+			workqueue.TypedRateLimitingQueueConfig[EventHandlerItem]{Name: name},
 		),
 	}
 }
@@ -35,6 +43,31 @@ func (c *Controller) Announce(obj interface{}) error {
 	return c.informer.GetStore().Add(obj)
 }
 
+// Reconcile enqueues the deletion of the given previously synchronized object if the informer,
+// which must have already synced, no longer has an object with the same key and UID.
+func (c *Controller) Reconcile(obj interface{}) error {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return errors.Wrap(err, "cannot make key")
+	}
+
+	item, exists, err := c.informer.GetStore().GetByKey(key)
+	if err != nil {
+		return errors.Wrapf(err, "fetching key %s failed", key)
+	}
+
+	uid := obj.(kmetav1.Object).GetUID()
+	if !exists || item.(kmetav1.Object).GetUID() != uid {
+		c.queue.Add(EventHandlerItem{
+			Type: EventDelete,
+			Id:   schemav1.EnsureUUID(uid),
+			KKey: key,
+		})
+	}
+
+	return nil
+}
+
 func (c *Controller) Stream(ctx context.Context, sink *Sink) error {
 	_, err := c.informer.AddEventHandler(NewEventHandler(c.queue, c.log.WithName("events")))
 	if err != nil {
@@ -48,7 +81,10 @@ func (c *Controller) Stream(ctx context.Context, sink *Sink) error {
 		c.queue.ShutDown()
 	}()
 
-	go c.informer.Run(ctx.Done())
+	// The informer may already be running, e.g. to keep the caches of leader election followers warm.
+	if !c.informer.HasSynced() {
+		go c.informer.Run(ctx.Done())
+	}
 
 	if !cache.WaitForCacheSync(ctx.Done(), c.informer.HasSynced) {
 		return errors.New("timed out waiting for caches to sync")
@@ -94,6 +130,8 @@ func (c *Controller) stream(ctx context.Context, sink *Sink) error {
 			if err := sink.Delete(ctx, eventHandlerItem.Id); err != nil {
 				return err
 			}
+
+			telemetry.SyncObjects.WithLabelValues(c.name, "delete").Inc()
 		} else {
 			obj := item.(kmetav1.Object)
 			err := sink.Upsert(ctx, &Item{
@@ -103,6 +141,8 @@ func (c *Controller) stream(ctx context.Context, sink *Sink) error {
 			if err != nil {
 				return err
 			}
+
+			telemetry.SyncObjects.WithLabelValues(c.name, "upsert").Inc()
 		}
 	}
 }
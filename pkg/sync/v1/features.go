@@ -1,14 +1,23 @@
 package v1
 
-import "github.com/icinga/icinga-go-library/database"
+import (
+	"github.com/icinga/icinga-go-library/database"
+	"github.com/icinga/icinga-kubernetes/pkg/metrics"
+	"github.com/icinga/icinga-kubernetes/pkg/rules"
+)
 
 type Feature func(*Features)
 
 type Features struct {
-	noDelete bool
-	noWarmup bool
-	onDelete database.OnSuccess[any]
-	onUpsert database.OnSuccess[any]
+	noDelete                bool
+	noWarmup                bool
+	onDelete                database.OnSuccess[any]
+	onUpsert                database.OnSuccess[any]
+	rollouts                *Rollouts
+	stateRules              *rules.Rules
+	thresholds              *metrics.Thresholds
+	warmupNamespaces        []string
+	warmupExcludeNamespaces []string
 }
 
 func NewFeatures(features ...Feature) *Features {
@@ -36,6 +45,22 @@ func (f *Features) OnUpsert() database.OnSuccess[any] {
 	return f.onUpsert
 }
 
+func (f *Features) Rollouts() *Rollouts {
+	return f.rollouts
+}
+
+func (f *Features) StateRules() *rules.Rules {
+	return f.stateRules
+}
+
+func (f *Features) Thresholds() *metrics.Thresholds {
+	return f.thresholds
+}
+
+func (f *Features) WarmupNamespaces() (include, exclude []string) {
+	return f.warmupNamespaces, f.warmupExcludeNamespaces
+}
+
 func WithNoDelete() Feature {
 	return func(f *Features) {
 		f.noDelete = true
@@ -59,3 +84,34 @@ func WithOnUpsert(fn database.OnSuccess[any]) Feature {
 		f.onUpsert = fn
 	}
 }
+
+// WithRollouts tracks the rollouts of the synchronized workloads.
+func WithRollouts(r *Rollouts) Feature {
+	return func(f *Features) {
+		f.rollouts = r
+	}
+}
+
+// WithStateRules applies the given rules to the Icinga states of the synchronized objects.
+func WithStateRules(r *rules.Rules) Feature {
+	return func(f *Features) {
+		f.stateRules = r
+	}
+}
+
+// WithThresholds merges the Icinga states derived from the metric thresholds of the synchronized objects
+// into their Icinga states.
+func WithThresholds(t *metrics.Thresholds) Feature {
+	return func(f *Features) {
+		f.thresholds = t
+	}
+}
+
+// WithWarmupNamespaces restricts the warmup to database rows of the namespaces in include, if any,
+// and excludes rows of the namespaces in exclude.
+func WithWarmupNamespaces(include, exclude []string) Feature {
+	return func(f *Features) {
+		f.warmupNamespaces = include
+		f.warmupExcludeNamespaces = exclude
+	}
+}
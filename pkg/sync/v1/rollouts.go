@@ -0,0 +1,203 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/icinga/icinga-go-library/types"
+	"github.com/icinga/icinga-kubernetes/pkg/database"
+	schemav1 "github.com/icinga/icinga-kubernetes/pkg/schema/v1"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+// Rollouts tracks the rollouts of workloads from their start until their end.
+// The rollouts are added to the workloads on every change, so that they are persisted together with them.
+// A rollout only starts if the pod template of a workload changes, i.e. its revision, not if it is merely scaled.
+type Rollouts struct {
+	db          *database.Database
+	clusterUuid types.UUID
+	mu          sync.Mutex
+	active      map[types.UUID]*schemav1.Rollout
+
+	// revisions are the revisions of the workloads that are rolled out by their active rollouts.
+	revisions map[types.UUID]string
+
+	// settled are the revisions of the workloads that were last observed while not updating.
+	settled map[types.UUID]string
+}
+
+// NewRollouts returns a new Rollouts that tracks the rollouts of workloads of the given cluster.
+func NewRollouts(db *database.Database, clusterUuid types.UUID) *Rollouts {
+	return &Rollouts{
+		db:          db,
+		clusterUuid: clusterUuid,
+		active:      make(map[types.UUID]*schemav1.Rollout),
+		revisions:   make(map[types.UUID]string),
+		settled:     make(map[types.UUID]string),
+	}
+}
+
+// Restore loads the rollouts of the cluster that have not ended yet, so that they are continued after a restart.
+// Rollouts of workloads that no longer exist are ended instead.
+func (r *Rollouts) Restore(ctx context.Context) error {
+	var rollouts []schemav1.Rollout
+	if err := r.db.SelectContext(
+		ctx,
+		&rollouts,
+		r.db.Rebind(fmt.Sprintf(
+			"%s WHERE %s = ? AND %s IS NULL",
+			r.db.BuildSelectStmt(schemav1.Rollout{}, schemav1.Rollout{}),
+			r.db.QuoteIdentifier("cluster_uuid"),
+			r.db.QuoteIdentifier("end_time"),
+		)),
+		r.clusterUuid,
+	); err != nil {
+		return errors.Wrap(err, "cannot select rollouts")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rollout := range rollouts {
+		rollout := rollout
+
+		var exists bool
+		if err := r.db.QueryRowxContext(
+			ctx,
+			r.db.Rebind(fmt.Sprintf(
+				"SELECT EXISTS (SELECT 1 FROM %s WHERE %s = ?)",
+				r.db.QuoteIdentifier(rollout.Kind), r.db.QuoteIdentifier("uuid"))),
+			rollout.ResourceUuid,
+		).Scan(&exists); err != nil {
+			return errors.Wrapf(err, "cannot check whether %s %s exists", rollout.Kind, rollout.ResourceUuid)
+		}
+
+		if !exists {
+			rollout.End(time.Now(), schemav1.RolloutDeleted)
+			if err := r.upsert(ctx, &rollout); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		r.active[rollout.ResourceUuid] = &rollout
+	}
+
+	return nil
+}
+
+// EndDeleted ends the active rollouts of the deleted workloads with the given UUIDs,
+// which would otherwise never end. It can be used as the OnSuccess callback of deletions.
+func (r *Rollouts) EndDeleted(ctx context.Context, resourceUuids []any) error {
+	var ended []*schemav1.Rollout
+	now := time.Now()
+
+	r.mu.Lock()
+	for _, id := range resourceUuids {
+		resourceUuid, ok := id.(types.UUID)
+		if !ok {
+			continue
+		}
+
+		if rollout, ok := r.active[resourceUuid]; ok {
+			rollout.End(now, schemav1.RolloutDeleted)
+			ended = append(ended, rollout)
+		}
+
+		delete(r.active, resourceUuid)
+		delete(r.revisions, resourceUuid)
+		delete(r.settled, resourceUuid)
+	}
+	r.mu.Unlock()
+
+	for _, rollout := range ended {
+		if err := r.upsert(ctx, rollout); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// upsert persists the given rollout on its own, i.e. without its workload.
+func (r *Rollouts) upsert(ctx context.Context, rollout *schemav1.Rollout) error {
+	stmt, _ := r.db.BuildUpsertStmt(rollout)
+	if _, err := r.db.NamedExecContext(ctx, stmt, rollout); err != nil {
+		return errors.Wrap(err, "cannot upsert rollout")
+	}
+
+	return nil
+}
+
+// Track starts, updates or ends the rollout of the given workload according to its rollout status
+// and adds the rollout to the workload if it has changed.
+func (r *Rollouts) Track(workload schemav1.Rolling) {
+	status := workload.GetRolloutStatus()
+	resourceUuid := schemav1.EnsureUUID(workload.GetUID())
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settled, known := r.settled[resourceUuid]
+	if !status.Updating {
+		r.settled[resourceUuid] = status.Revision
+	}
+
+	rollout, ok := r.active[resourceUuid]
+	if ok {
+		// The revision of rollouts restored after a restart is not persisted, so it is taken from the workload.
+		if _, ok := r.revisions[resourceUuid]; !ok {
+			r.revisions[resourceUuid] = status.Revision
+		}
+	}
+
+	if ok && status.Updating && status.Revision != r.revisions[resourceUuid] {
+		rollout.End(now, schemav1.RolloutSuperseded)
+		workload.AddRollout(*rollout)
+		// The superseded revision has never settled, but the new one is nonetheless a change of it.
+		settled, known = r.revisions[resourceUuid], true
+		delete(r.active, resourceUuid)
+		delete(r.revisions, resourceUuid)
+		ok = false
+	}
+
+	switch {
+	case !ok:
+		// Rollouts are only started by replicas that have yet to be updated to a new revision,
+		// not by replicas that are merely unavailable or added by scaling. Workloads that have not been observed
+		// with a settled revision yet, e.g. because they have just been created, do not start rollouts either.
+		if !status.Updating || !known || status.Revision == settled {
+			return
+		}
+
+		rollout = &schemav1.Rollout{
+			Uuid:         types.UUID{UUID: uuid.New()},
+			ClusterUuid:  r.clusterUuid,
+			ResourceUuid: resourceUuid,
+			Kind:         database.TableName(workload),
+			Namespace:    workload.GetNamespace(),
+			Name:         workload.GetName(),
+			Generation:   status.Generation,
+			StartTime:    types.UnixMilli(now),
+			Outcome:      status.Outcome(),
+			Message:      status.Message,
+		}
+		r.active[resourceUuid] = rollout
+		r.revisions[resourceUuid] = status.Revision
+	case !status.Progressing:
+		rollout.End(now, schemav1.RolloutComplete)
+		delete(r.active, resourceUuid)
+		delete(r.revisions, resourceUuid)
+	case rollout.Outcome != status.Outcome() || rollout.Message != status.Message:
+		rollout.Outcome = status.Outcome()
+		rollout.Message = status.Message
+	default:
+		return
+	}
+
+	workload.AddRollout(*rollout)
+}
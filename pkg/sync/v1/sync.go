@@ -7,9 +7,11 @@ import (
 	"github.com/icinga/icinga-kubernetes/pkg/cluster"
 	"github.com/icinga/icinga-kubernetes/pkg/database"
 	schemav1 "github.com/icinga/icinga-kubernetes/pkg/schema/v1"
+	"github.com/icinga/icinga-kubernetes/pkg/telemetry"
 	"golang.org/x/sync/errgroup"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
+	"strings"
 )
 
 type Sync struct {
@@ -34,12 +36,12 @@ func NewSync(
 }
 
 func (s *Sync) Run(ctx context.Context, features ...Feature) error {
-	controller := NewController(s.informer, s.log.WithName("controller"))
+	controller := NewController(s.informer, database.TableName(s.factory()), s.log.WithName("controller"))
 
 	with := NewFeatures(features...)
 
 	if !with.NoWarmup() {
-		if err := s.warmup(ctx, controller); err != nil {
+		if err := s.warmup(ctx, controller, with); err != nil {
 			return err
 		}
 	}
@@ -47,19 +49,47 @@ func (s *Sync) Run(ctx context.Context, features ...Feature) error {
 	return s.sync(ctx, controller, features...)
 }
 
-func (s *Sync) warmup(ctx context.Context, c *Controller) error {
+func (s *Sync) warmup(ctx context.Context, c *Controller, with *Features) error {
 	g, ctx := errgroup.WithContext(ctx)
 
 	meta := &schemav1.Meta{ClusterUuid: cluster.ClusterUuidFromContext(ctx)}
 	query := s.db.BuildSelectStmt(s.factory(), meta) + ` WHERE cluster_uuid=:cluster_uuid`
+	args := []any{meta}
+
+	// A lone struct argument is bound to named parameters, so namespace filters use positional parameters only,
+	// which are bound as is.
+	include, exclude := with.WarmupNamespaces()
+	if len(include) > 0 || len(exclude) > 0 {
+		query = s.db.BuildSelectStmt(s.factory(), meta) + ` WHERE cluster_uuid = ?`
+		args = []any{meta.ClusterUuid}
+	}
+	if len(include) > 0 {
+		query += ` AND namespace IN (?` + strings.Repeat(`, ?`, len(include)-1) + `)`
+		for _, namespace := range include {
+			args = append(args, namespace)
+		}
+	}
+	if len(exclude) > 0 {
+		query += ` AND namespace NOT IN (?` + strings.Repeat(`, ?`, len(exclude)-1) + `)`
+		for _, namespace := range exclude {
+			args = append(args, namespace)
+		}
+	}
 
 	entities, errs := s.db.YieldAll(ctx, func() (interface{}, error) {
 		return s.factory(), nil
-	}, query, meta)
+	}, query, args...)
 
 	// Let errors from YieldAll() cancel the group.
 	com.ErrgroupReceive(g, errs)
 
+	// If the informer has already synced, e.g. because it has been kept warm while waiting for leadership,
+	// announcing the entities would not cause deletions anymore, so they are reconciled with its store instead.
+	announce := c.Announce
+	if s.informer.HasSynced() {
+		announce = c.Reconcile
+	}
+
 	g.Go(func() error {
 		defer runtime.HandleCrash()
 
@@ -70,7 +100,7 @@ func (s *Sync) warmup(ctx context.Context, c *Controller) error {
 					return nil
 				}
 
-				if err := c.Announce(e); err != nil {
+				if err := announce(e); err != nil {
 					return err
 				}
 			case <-ctx.Done():
@@ -83,17 +113,41 @@ func (s *Sync) warmup(ctx context.Context, c *Controller) error {
 }
 
 func (s *Sync) sync(ctx context.Context, c *Controller, features ...Feature) error {
+	with := NewFeatures(features...)
+
 	sink := NewSink(func(i *Item) interface{} {
 		entity := s.factory()
 		entity.Obtain(*i.Item, cluster.ClusterUuidFromContext(ctx))
 
+		if thresholds := with.Thresholds(); thresholds != nil {
+			if stater, ok := entity.(schemav1.IcingaStater); ok {
+				thresholds.Apply(*i.Item, stater)
+			}
+		}
+
+		if stateRules := with.StateRules(); stateRules != nil {
+			if stater, ok := entity.(schemav1.IcingaStater); ok {
+				if err := stateRules.Apply(*i.Item, stater); err != nil {
+					s.log.Error(err, "Cannot apply state rules", "name", entity.GetName())
+				}
+			}
+		}
+
+		if stater, ok := entity.(schemav1.IcingaStater); ok {
+			schemav1.ApplyStateOverrides(*i.Item, stater)
+		}
+
+		if rollouts := with.Rollouts(); rollouts != nil {
+			if workload, ok := entity.(schemav1.Rolling); ok {
+				rollouts.Track(workload)
+			}
+		}
+
 		return entity
 	}, func(k interface{}) interface{} {
 		return k
 	})
 
-	with := NewFeatures(features...)
-
 	g, ctx := errgroup.WithContext(ctx)
 	g.Go(func() error {
 		defer runtime.HandleCrash()
@@ -123,9 +177,26 @@ func (s *Sync) sync(ctx context.Context, c *Controller, features ...Feature) err
 
 			}
 		} else {
+			onDelete := with.OnDelete()
+			if rollouts := with.Rollouts(); rollouts != nil {
+				// Rollouts of deleted workloads are ended, as they would otherwise remain active forever.
+				forward := onDelete
+				onDelete = func(ctx context.Context, ids []any) error {
+					if err := rollouts.EndDeleted(ctx, ids); err != nil {
+						return err
+					}
+
+					if forward != nil {
+						return forward(ctx, ids)
+					}
+
+					return nil
+				}
+			}
+
 			return s.db.DeleteStreamed(
 				ctx, s.factory(), sink.DeleteCh(),
-				database.WithBlocking(), database.WithCascading(), database.WithOnSuccess(with.OnDelete()))
+				database.WithBlocking(), database.WithCascading(), database.WithOnSuccess(onDelete))
 		}
 	})
 	g.Go(func() error {
@@ -139,6 +210,7 @@ func (s *Sync) sync(ctx context.Context, c *Controller, features ...Feature) err
 				}
 
 				s.log.Error(err, "sync error")
+				telemetry.SyncErrors.WithLabelValues(database.TableName(s.factory())).Inc()
 			case <-ctx.Done():
 				return ctx.Err()
 			}
@@ -0,0 +1,7 @@
+package telemetry
+
+// Config defines the configuration of the HTTP server that exposes health checks and metrics.
+type Config struct {
+	// Listen is the address to listen on, e.g. ":8080". If empty, the HTTP server is disabled.
+	Listen string `yaml:"listen"`
+}
@@ -0,0 +1,77 @@
+package telemetry
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/cache"
+	"sync"
+)
+
+// Readiness tracks whether the informers of the synchronizations of all clusters have synced.
+type Readiness struct {
+	mu sync.Mutex
+	// clusters maps the names of the expected clusters to the informers of their running synchronization,
+	// which are nil until the synchronization has registered them, e.g. while waiting for leadership.
+	clusters map[string]map[string]cache.InformerSynced
+}
+
+// DefaultReadiness is the Readiness that the synchronizations register their informers with.
+var DefaultReadiness = &Readiness{}
+
+// Expect registers the cluster with the given name, which is not ready until its informers have been registered
+// via Register and have synced.
+func (r *Readiness) Expect(cluster string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.clusters == nil {
+		r.clusters = make(map[string]map[string]cache.InformerSynced)
+	}
+
+	if _, ok := r.clusters[cluster]; !ok {
+		r.clusters[cluster] = nil
+	}
+}
+
+// Register registers the synced functions of the informers by name of all synchronizations of the given cluster.
+// The returned function unregisters them once the synchronization stops, after which the cluster is not ready
+// until its informers are registered again.
+func (r *Readiness) Register(cluster string, informers map[string]cache.InformerSynced) (unregister func()) {
+	r.Expect(cluster)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clusters[cluster] = informers
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.clusters[cluster] = nil
+	}
+}
+
+// Check returns an error naming a cluster that is not synchronizing or an informer that has not synced yet, if any.
+func (r *Readiness) Check(context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.clusters) == 0 {
+		return errors.New("no cluster is synchronized yet")
+	}
+
+	for cluster, informers := range r.clusters {
+		if informers == nil {
+			return errors.Errorf("cluster %q is not synchronized yet", cluster)
+		}
+
+		for name, synced := range informers {
+			if !synced() {
+				return errors.Errorf("informer %q of cluster %q has not synced yet", name, cluster)
+			}
+		}
+	}
+
+	return nil
+}
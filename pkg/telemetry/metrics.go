@@ -0,0 +1,182 @@
+package telemetry
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const namespace = "icinga_kubernetes"
+
+var (
+	// SyncObjects counts the objects that have been upserted or deleted per resource.
+	SyncObjects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "sync",
+		Name:      "objects_total",
+		Help:      "Number of objects that have been synchronized by resource and operation.",
+	}, []string{"resource", "operation"})
+
+	// SyncErrors counts the errors that occurred while synchronizing objects per resource.
+	SyncErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "sync",
+		Name:      "errors_total",
+		Help:      "Number of errors that occurred while synchronizing objects by resource.",
+	}, []string{"resource"})
+
+	// DatabaseBatchDuration observes the duration of upsert and delete batches per table.
+	DatabaseBatchDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "database",
+		Name:      "batch_duration_seconds",
+		Help:      "Duration of upsert and delete batches by table and operation.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 15),
+	}, []string{"table", "operation"})
+
+	// NotificationsSent counts the events that have been sent per notification sink.
+	NotificationsSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "notifications",
+		Name:      "sent_total",
+		Help:      "Number of events that have been sent by sink.",
+	}, []string{"sink"})
+
+	// NotificationsFailures counts the events that could not be sent per notification sink.
+	NotificationsFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "notifications",
+		Name:      "send_failures_total",
+		Help:      "Number of events that could not be sent by sink.",
+	}, []string{"sink"})
+
+	// NotificationsRetries counts the retried attempts to send events per notification sink.
+	NotificationsRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "notifications",
+		Name:      "retries_total",
+		Help:      "Number of retried attempts to send events by sink.",
+	}, []string{"sink"})
+
+	// NotificationsDropped counts the events that have been dropped per notification sink because its queue was full.
+	NotificationsDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "notifications",
+		Name:      "dropped_total",
+		Help:      "Number of events that have been dropped because the notifications queue was full by sink.",
+	}, []string{"sink"})
+
+	// NotificationsPending is the number of events that are queued for delivery per notification sink.
+	NotificationsPending = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "notifications",
+		Name:      "pending",
+		Help:      "Number of events that are queued for delivery by sink.",
+	}, []string{"sink"})
+)
+
+var (
+	workqueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "workqueue",
+		Name:      "depth",
+		Help:      "Current depth of the workqueue by name.",
+	}, []string{"name"})
+
+	workqueueAdds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "workqueue",
+		Name:      "adds_total",
+		Help:      "Number of adds handled by the workqueue by name.",
+	}, []string{"name"})
+
+	workqueueLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "workqueue",
+		Name:      "queue_duration_seconds",
+		Help:      "Duration that items stay in the workqueue before being requested by name.",
+		Buckets:   prometheus.ExponentialBuckets(10e-9, 10, 12),
+	}, []string{"name"})
+
+	workqueueWorkDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "workqueue",
+		Name:      "work_duration_seconds",
+		Help:      "Duration of processing items from the workqueue by name.",
+		Buckets:   prometheus.ExponentialBuckets(10e-9, 10, 12),
+	}, []string{"name"})
+
+	workqueueUnfinishedWork = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "workqueue",
+		Name:      "unfinished_work_seconds",
+		Help:      "Duration of work in progress that has not been observed by work_duration_seconds yet by name.",
+	}, []string{"name"})
+
+	workqueueLongestRunningProcessor = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "workqueue",
+		Name:      "longest_running_processor_seconds",
+		Help:      "Duration of the longest running processor of the workqueue by name.",
+	}, []string{"name"})
+
+	workqueueRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "workqueue",
+		Name:      "retries_total",
+		Help:      "Number of retries handled by the workqueue by name.",
+	}, []string{"name"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		SyncObjects,
+		SyncErrors,
+		DatabaseBatchDuration,
+		NotificationsSent,
+		NotificationsFailures,
+		NotificationsRetries,
+		NotificationsDropped,
+		NotificationsPending,
+		workqueueDepth,
+		workqueueAdds,
+		workqueueLatency,
+		workqueueWorkDuration,
+		workqueueUnfinishedWork,
+		workqueueLongestRunningProcessor,
+		workqueueRetries,
+	)
+
+	workqueue.SetProvider(workqueueMetricsProvider{})
+}
+
+// workqueueMetricsProvider implements workqueue.MetricsProvider
+// to expose the metrics of named workqueues, e.g. the ones of the sync controllers.
+type workqueueMetricsProvider struct{}
+
+func (workqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return workqueueDepth.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return workqueueAdds.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return workqueueLatency.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return workqueueWorkDuration.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return workqueueUnfinishedWork.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return workqueueLongestRunningProcessor.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return workqueueRetries.WithLabelValues(name)
+}
@@ -0,0 +1,67 @@
+package telemetry
+
+import (
+	"context"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"time"
+)
+
+// Check is a readiness check that returns an error if not ready.
+type Check func(context.Context) error
+
+// Serve serves /healthz, /readyz and /metrics on the configured address until ctx is canceled.
+// /healthz always responds OK while the daemon is running. /readyz responds OK
+// once all given checks, e.g. whether the database is reachable, and DefaultReadiness pass.
+func Serve(ctx context.Context, config *Config, log logr.Logger, checks ...Check) error {
+	checks = append(checks, DefaultReadiness.Check)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		for _, check := range checks {
+			if err := check(ctx); err != nil {
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+
+				return
+			}
+		}
+
+		_, _ = w.Write([]byte("ok"))
+	})
+	mux.Handle("/metrics", promhttp.Handler())
+
+	server := &http.Server{
+		Addr:              config.Listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		log.Info("Serving health checks and metrics", "address", config.Listen)
+
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			errs <- errors.Wrap(err, "cannot serve health checks and metrics")
+		}
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_ = server.Shutdown(shutdownCtx)
+
+		return ctx.Err()
+	}
+}
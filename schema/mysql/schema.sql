@@ -155,6 +155,8 @@ CREATE TABLE cron_job (
   last_schedule_time bigint unsigned NULL DEFAULT NULL,
   last_successful_time bigint unsigned NULL DEFAULT NULL,
   yaml mediumblob DEFAULT NULL,
+  icinga_state enum('unknown', 'pending', 'ok', 'warning', 'critical') COLLATE utf8mb4_unicode_ci NOT NULL,
+  icinga_state_reason text NOT NULL,
   created bigint unsigned NOT NULL,
   PRIMARY KEY (uuid)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
@@ -355,6 +357,63 @@ CREATE TABLE event (
   PRIMARY KEY (uuid)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
 
+CREATE TABLE hpa (
+  uuid binary(16) NOT NULL,
+  cluster_uuid binary(16) NOT NULL,
+  namespace varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  name varchar(253) COLLATE utf8mb4_unicode_ci NOT NULL,
+  uid varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  resource_version varchar(255) NOT NULL,
+  scale_target_kind varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  scale_target_name varchar(253) COLLATE utf8mb4_unicode_ci NOT NULL,
+  min_replicas int unsigned NOT NULL,
+  max_replicas int unsigned NOT NULL,
+  current_replicas int unsigned NOT NULL,
+  desired_replicas int unsigned NOT NULL,
+  last_scale bigint unsigned NULL DEFAULT NULL,
+  yaml mediumblob DEFAULT NULL,
+  icinga_state enum('unknown', 'pending', 'ok', 'warning', 'critical') COLLATE utf8mb4_unicode_ci NOT NULL,
+  icinga_state_reason text NOT NULL,
+  created bigint unsigned NOT NULL,
+  PRIMARY KEY (uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE hpa_annotation (
+  hpa_uuid binary(16) NOT NULL,
+  annotation_uuid binary(16) NOT NULL,
+  PRIMARY KEY (hpa_uuid, annotation_uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE hpa_condition (
+  hpa_uuid binary(16) NOT NULL,
+  type varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  status enum('true', 'false', 'unknown') COLLATE utf8mb4_unicode_ci NOT NULL,
+  last_transition bigint unsigned NOT NULL,
+  reason varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  message text,
+  PRIMARY KEY (hpa_uuid, type)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE hpa_label (
+  hpa_uuid binary(16) NOT NULL,
+  label_uuid binary(16) NOT NULL,
+  PRIMARY KEY (hpa_uuid, label_uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE hpa_metric (
+  uuid binary(16) NOT NULL,
+  hpa_uuid binary(16) NOT NULL,
+  type enum('ContainerResource', 'External', 'Object', 'Pods', 'Resource') COLLATE utf8mb4_unicode_ci NOT NULL,
+  name varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  container varchar(63) COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  target_type enum('AverageValue', 'Utilization', 'Value') COLLATE utf8mb4_unicode_ci NOT NULL,
+  target_utilization int unsigned NULL DEFAULT NULL,
+  target_value varchar(255) COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  current_utilization int unsigned NULL DEFAULT NULL,
+  current_value varchar(255) COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  PRIMARY KEY (uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
 CREATE TABLE ingress (
   uuid binary(16) NOT NULL,
   cluster_uuid binary(16) NOT NULL,
@@ -363,6 +422,8 @@ CREATE TABLE ingress (
   uid varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
   resource_version varchar(255) NOT NULL,
   yaml mediumblob DEFAULT NULL,
+  icinga_state enum('unknown', 'pending', 'ok', 'warning', 'critical') COLLATE utf8mb4_unicode_ci NOT NULL,
+  icinga_state_reason text NOT NULL,
   created bigint unsigned NOT NULL,
   PRIMARY KEY (uuid)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
@@ -485,6 +546,8 @@ CREATE TABLE namespace (
   resource_version varchar(255) NOT NULL,
   phase enum('Active', 'Terminating') COLLATE utf8mb4_unicode_ci NOT NULL,
   yaml mediumblob DEFAULT NULL,
+  icinga_state enum('unknown', 'pending', 'ok', 'warning', 'critical') COLLATE utf8mb4_unicode_ci NOT NULL,
+  icinga_state_reason text NOT NULL,
   created bigint unsigned NOT NULL,
   PRIMARY KEY (uuid)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
@@ -568,6 +631,16 @@ CREATE TABLE node_label (
   PRIMARY KEY (node_uuid, label_uuid)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
 
+CREATE TABLE node_metrics (
+  cluster_uuid binary(16) NOT NULL,
+  node_name varchar(253) NOT NULL,
+  timestamp bigint unsigned NOT NULL,
+  duration bigint unsigned NOT NULL,
+  cpu_usage float NOT NULL,
+  memory_usage float NOT NULL,
+  PRIMARY KEY (cluster_uuid, node_name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
 CREATE TABLE node_volume (
   node_uuid binary(16) NOT NULL,
   name varchar(253) COLLATE utf8mb4_unicode_ci NOT NULL,
@@ -576,6 +649,55 @@ CREATE TABLE node_volume (
   PRIMARY KEY (node_uuid, name)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
 
+CREATE TABLE pdb (
+  uuid binary(16) NOT NULL,
+  cluster_uuid binary(16) NOT NULL,
+  namespace varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  name varchar(253) COLLATE utf8mb4_unicode_ci NOT NULL,
+  uid varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  resource_version varchar(255) NOT NULL,
+  min_available varchar(255) COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  max_unavailable varchar(255) COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  unhealthy_pod_eviction_policy enum('AlwaysAllow', 'IfHealthyBudget') COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  current_healthy int unsigned NOT NULL,
+  desired_healthy int unsigned NOT NULL,
+  disruptions_allowed int unsigned NOT NULL,
+  expected_pods int unsigned NOT NULL,
+  yaml mediumblob DEFAULT NULL,
+  icinga_state enum('unknown', 'pending', 'ok', 'warning', 'critical') COLLATE utf8mb4_unicode_ci NOT NULL,
+  icinga_state_reason text NOT NULL,
+  created bigint unsigned NOT NULL,
+  PRIMARY KEY (uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE pdb_annotation (
+  pdb_uuid binary(16) NOT NULL,
+  annotation_uuid binary(16) NOT NULL,
+  PRIMARY KEY (pdb_uuid, annotation_uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE pdb_condition (
+  pdb_uuid binary(16) NOT NULL,
+  type varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  status enum('true', 'false', 'unknown') COLLATE utf8mb4_unicode_ci NOT NULL,
+  last_transition bigint unsigned NOT NULL,
+  reason varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  message text,
+  PRIMARY KEY (pdb_uuid, type)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE pdb_label (
+  pdb_uuid binary(16) NOT NULL,
+  label_uuid binary(16) NOT NULL,
+  PRIMARY KEY (pdb_uuid, label_uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE pdb_pod (
+  pdb_uuid binary(16) NOT NULL,
+  pod_uuid binary(16) NOT NULL,
+  PRIMARY KEY (pdb_uuid, pod_uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
 CREATE TABLE persistent_volume (
   uuid binary(16) NOT NULL,
   cluster_uuid binary(16) NOT NULL,
@@ -594,6 +716,8 @@ CREATE TABLE persistent_volume (
   volume_source longtext NOT NULL,
   reclaim_policy enum('Recycle', 'Delete', 'Retain') COLLATE utf8mb4_unicode_ci NOT NULL,
   yaml mediumblob DEFAULT NULL,
+  icinga_state enum('unknown', 'pending', 'ok', 'warning', 'critical') COLLATE utf8mb4_unicode_ci NOT NULL,
+  icinga_state_reason text NOT NULL,
   created bigint unsigned NOT NULL,
   PRIMARY KEY (uuid)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
@@ -668,6 +792,7 @@ CREATE TABLE pod_label (
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
 
 CREATE TABLE pod_metrics (
+  cluster_uuid binary(16) NOT NULL,
   namespace varchar(255) NOT NULL,
   pod_name varchar(253) NOT NULL,
   container_name varchar(255) NOT NULL,
@@ -677,7 +802,7 @@ CREATE TABLE pod_metrics (
   memory_usage float NOT NULL,
   storage_usage float NOT NULL,
   ephemeral_storage_usage float NOT NULL,
-  PRIMARY KEY (namespace, pod_name)
+  PRIMARY KEY (cluster_uuid, namespace, pod_name, container_name)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
 
 CREATE TABLE pod_owner (
@@ -759,6 +884,8 @@ CREATE TABLE pvc (
   volume_mode enum('Block', 'Filesystem') COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
   storage_class varchar(255) COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
   yaml mediumblob DEFAULT NULL,
+  icinga_state enum('unknown', 'pending', 'ok', 'warning', 'critical') COLLATE utf8mb4_unicode_ci NOT NULL,
+  icinga_state_reason text NOT NULL,
   created bigint unsigned NOT NULL,
   PRIMARY KEY (uuid)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
@@ -839,6 +966,22 @@ CREATE TABLE replica_set_owner (
   PRIMARY KEY (replica_set_uuid, owner_uuid)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
 
+CREATE TABLE rollout (
+  uuid binary(16) NOT NULL,
+  cluster_uuid binary(16) NOT NULL,
+  resource_uuid binary(16) NOT NULL,
+  kind enum('daemon_set', 'deployment', 'stateful_set') COLLATE utf8mb4_unicode_ci NOT NULL,
+  namespace varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  name varchar(253) COLLATE utf8mb4_unicode_ci NOT NULL,
+  generation bigint unsigned NOT NULL,
+  start_time bigint unsigned NOT NULL,
+  end_time bigint unsigned NULL DEFAULT NULL,
+  duration bigint unsigned NULL DEFAULT NULL,
+  outcome enum('progressing', 'stalled', 'complete', 'superseded', 'deleted') COLLATE utf8mb4_unicode_ci NOT NULL,
+  message text NOT NULL,
+  PRIMARY KEY (uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
 CREATE TABLE secret (
   uuid binary(16) NOT NULL,
   cluster_uuid binary(16) NOT NULL,
@@ -893,6 +1036,8 @@ CREATE TABLE service (
   load_balancer_class varchar(255) COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
   internal_traffic_policy enum('Cluster', 'Local') COLLATE utf8mb4_unicode_ci NOT NULL,
   yaml mediumblob DEFAULT NULL,
+  icinga_state enum('unknown', 'pending', 'ok', 'warning', 'critical') COLLATE utf8mb4_unicode_ci NOT NULL,
+  icinga_state_reason text NOT NULL,
   created bigint unsigned NOT NULL,
   PRIMARY KEY (uuid)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
@@ -1011,6 +1156,7 @@ CREATE TABLE kubernetes_instance (
   kubernetes_heartbeat bigint unsigned NULL DEFAULT NULL,
   kubernetes_api_reachable enum('n', 'y') COLLATE utf8mb4_unicode_ci NOT NULL,
   message text NULL DEFAULT NULL,
+  role enum('leader', 'follower') COLLATE utf8mb4_unicode_ci NOT NULL,
   heartbeat bigint unsigned NOT NULL,
   PRIMARY KEY (uuid)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin ROW_FORMAT=DYNAMIC;
@@ -1024,7 +1170,14 @@ CREATE TABLE config (
     'notifications.kubernetes_web_url',
     'prometheus.url',
     'prometheus.username',
-    'prometheus.password'
+    'prometheus.password',
+    'retention.disabled',
+    'retention.events',
+    'retention.cluster_metrics',
+    'retention.node_metrics',
+    'retention.pod_metrics',
+    'retention.container_metrics',
+    'retention.rollouts'
     ) COLLATE utf8mb4_unicode_ci NOT NULL,
   value varchar(255) NOT NULL,
   locked enum('n', 'y') COLLATE utf8mb4_unicode_ci NOT NULL,
@@ -1032,6 +1185,27 @@ CREATE TABLE config (
   PRIMARY KEY (`key`, cluster_uuid)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
 
+CREATE TABLE notification_outbox (
+  uuid binary(16) NOT NULL,
+  sink varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  cluster_uuid binary(16) NOT NULL,
+  event mediumtext NOT NULL,
+  created bigint unsigned NOT NULL,
+  PRIMARY KEY (uuid, sink)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE notification_silence (
+  uuid binary(16) NOT NULL,
+  cluster_uuid binary(16) NOT NULL,
+  silenced_until bigint unsigned NULL DEFAULT NULL,
+  maintenance enum('n', 'y') COLLATE utf8mb4_unicode_ci NOT NULL,
+  namespace varchar(255) COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  since bigint unsigned NOT NULL,
+  changes int unsigned NOT NULL,
+  event mediumtext NOT NULL,
+  PRIMARY KEY (uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
 CREATE TABLE kubernetes_schema (
   id int unsigned NOT NULL AUTO_INCREMENT,
   version varchar(255) NOT NULL,
@@ -1043,4 +1217,4 @@ CREATE TABLE kubernetes_schema (
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
 
 INSERT INTO kubernetes_schema (version, timestamp, success, reason)
-VALUES ('0.2.0', UNIX_TIMESTAMP() * 1000, 'y', 'Initial import');
+VALUES ('0.3.0', UNIX_TIMESTAMP() * 1000, 'y', 'Initial import');
@@ -0,0 +1,211 @@
+CREATE TABLE hpa (
+  uuid binary(16) NOT NULL,
+  cluster_uuid binary(16) NOT NULL,
+  namespace varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  name varchar(253) COLLATE utf8mb4_unicode_ci NOT NULL,
+  uid varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  resource_version varchar(255) NOT NULL,
+  scale_target_kind varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  scale_target_name varchar(253) COLLATE utf8mb4_unicode_ci NOT NULL,
+  min_replicas int unsigned NOT NULL,
+  max_replicas int unsigned NOT NULL,
+  current_replicas int unsigned NOT NULL,
+  desired_replicas int unsigned NOT NULL,
+  last_scale bigint unsigned NULL DEFAULT NULL,
+  yaml mediumblob DEFAULT NULL,
+  icinga_state enum('unknown', 'pending', 'ok', 'warning', 'critical') COLLATE utf8mb4_unicode_ci NOT NULL,
+  icinga_state_reason text NOT NULL,
+  created bigint unsigned NOT NULL,
+  PRIMARY KEY (uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE hpa_annotation (
+  hpa_uuid binary(16) NOT NULL,
+  annotation_uuid binary(16) NOT NULL,
+  PRIMARY KEY (hpa_uuid, annotation_uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE hpa_condition (
+  hpa_uuid binary(16) NOT NULL,
+  type varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  status enum('true', 'false', 'unknown') COLLATE utf8mb4_unicode_ci NOT NULL,
+  last_transition bigint unsigned NOT NULL,
+  reason varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  message text,
+  PRIMARY KEY (hpa_uuid, type)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE hpa_label (
+  hpa_uuid binary(16) NOT NULL,
+  label_uuid binary(16) NOT NULL,
+  PRIMARY KEY (hpa_uuid, label_uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE hpa_metric (
+  uuid binary(16) NOT NULL,
+  hpa_uuid binary(16) NOT NULL,
+  type enum('ContainerResource', 'External', 'Object', 'Pods', 'Resource') COLLATE utf8mb4_unicode_ci NOT NULL,
+  name varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  container varchar(63) COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  target_type enum('AverageValue', 'Utilization', 'Value') COLLATE utf8mb4_unicode_ci NOT NULL,
+  target_utilization int unsigned NULL DEFAULT NULL,
+  target_value varchar(255) COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  current_utilization int unsigned NULL DEFAULT NULL,
+  current_value varchar(255) COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  PRIMARY KEY (uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE pdb (
+  uuid binary(16) NOT NULL,
+  cluster_uuid binary(16) NOT NULL,
+  namespace varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  name varchar(253) COLLATE utf8mb4_unicode_ci NOT NULL,
+  uid varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  resource_version varchar(255) NOT NULL,
+  min_available varchar(255) COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  max_unavailable varchar(255) COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  unhealthy_pod_eviction_policy enum('AlwaysAllow', 'IfHealthyBudget') COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  current_healthy int unsigned NOT NULL,
+  desired_healthy int unsigned NOT NULL,
+  disruptions_allowed int unsigned NOT NULL,
+  expected_pods int unsigned NOT NULL,
+  yaml mediumblob DEFAULT NULL,
+  icinga_state enum('unknown', 'pending', 'ok', 'warning', 'critical') COLLATE utf8mb4_unicode_ci NOT NULL,
+  icinga_state_reason text NOT NULL,
+  created bigint unsigned NOT NULL,
+  PRIMARY KEY (uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE pdb_annotation (
+  pdb_uuid binary(16) NOT NULL,
+  annotation_uuid binary(16) NOT NULL,
+  PRIMARY KEY (pdb_uuid, annotation_uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE pdb_condition (
+  pdb_uuid binary(16) NOT NULL,
+  type varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  status enum('true', 'false', 'unknown') COLLATE utf8mb4_unicode_ci NOT NULL,
+  last_transition bigint unsigned NOT NULL,
+  reason varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  message text,
+  PRIMARY KEY (pdb_uuid, type)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE pdb_label (
+  pdb_uuid binary(16) NOT NULL,
+  label_uuid binary(16) NOT NULL,
+  PRIMARY KEY (pdb_uuid, label_uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE pdb_pod (
+  pdb_uuid binary(16) NOT NULL,
+  pod_uuid binary(16) NOT NULL,
+  PRIMARY KEY (pdb_uuid, pod_uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+ALTER TABLE pvc
+  ADD COLUMN icinga_state enum('unknown', 'pending', 'ok', 'warning', 'critical') COLLATE utf8mb4_unicode_ci NOT NULL AFTER yaml,
+  ADD COLUMN icinga_state_reason text NOT NULL AFTER icinga_state;
+
+ALTER TABLE persistent_volume
+  ADD COLUMN icinga_state enum('unknown', 'pending', 'ok', 'warning', 'critical') COLLATE utf8mb4_unicode_ci NOT NULL AFTER yaml,
+  ADD COLUMN icinga_state_reason text NOT NULL AFTER icinga_state;
+
+ALTER TABLE cron_job
+  ADD COLUMN icinga_state enum('unknown', 'pending', 'ok', 'warning', 'critical') COLLATE utf8mb4_unicode_ci NOT NULL AFTER yaml,
+  ADD COLUMN icinga_state_reason text NOT NULL AFTER icinga_state;
+
+ALTER TABLE service
+  ADD COLUMN icinga_state enum('unknown', 'pending', 'ok', 'warning', 'critical') COLLATE utf8mb4_unicode_ci NOT NULL AFTER yaml,
+  ADD COLUMN icinga_state_reason text NOT NULL AFTER icinga_state;
+
+ALTER TABLE ingress
+  ADD COLUMN icinga_state enum('unknown', 'pending', 'ok', 'warning', 'critical') COLLATE utf8mb4_unicode_ci NOT NULL AFTER yaml,
+  ADD COLUMN icinga_state_reason text NOT NULL AFTER icinga_state;
+
+ALTER TABLE config MODIFY COLUMN `key` enum(
+    'notifications.url',
+    'notifications.username',
+    'notifications.password',
+    'notifications.kubernetes_web_url',
+    'prometheus.url',
+    'prometheus.username',
+    'prometheus.password',
+    'retention.disabled',
+    'retention.events',
+    'retention.cluster_metrics',
+    'retention.node_metrics',
+    'retention.pod_metrics',
+    'retention.container_metrics',
+    'retention.rollouts'
+    ) COLLATE utf8mb4_unicode_ci NOT NULL;
+
+ALTER TABLE kubernetes_instance
+  ADD COLUMN role enum('leader', 'follower') COLLATE utf8mb4_unicode_ci NOT NULL AFTER message;
+
+CREATE TABLE notification_outbox (
+  uuid binary(16) NOT NULL,
+  sink varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  cluster_uuid binary(16) NOT NULL,
+  event mediumtext NOT NULL,
+  created bigint unsigned NOT NULL,
+  PRIMARY KEY (uuid, sink)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE notification_silence (
+  uuid binary(16) NOT NULL,
+  cluster_uuid binary(16) NOT NULL,
+  silenced_until bigint unsigned NULL DEFAULT NULL,
+  maintenance enum('n', 'y') COLLATE utf8mb4_unicode_ci NOT NULL,
+  namespace varchar(255) COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  since bigint unsigned NOT NULL,
+  changes int unsigned NOT NULL,
+  event mediumtext NOT NULL,
+  PRIMARY KEY (uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+ALTER TABLE namespace
+  ADD COLUMN icinga_state enum('unknown', 'pending', 'ok', 'warning', 'critical') COLLATE utf8mb4_unicode_ci NOT NULL AFTER yaml,
+  ADD COLUMN icinga_state_reason text NOT NULL AFTER icinga_state;
+
+CREATE TABLE rollout (
+  uuid binary(16) NOT NULL,
+  cluster_uuid binary(16) NOT NULL,
+  resource_uuid binary(16) NOT NULL,
+  kind enum('daemon_set', 'deployment', 'stateful_set') COLLATE utf8mb4_unicode_ci NOT NULL,
+  namespace varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  name varchar(253) COLLATE utf8mb4_unicode_ci NOT NULL,
+  generation bigint unsigned NOT NULL,
+  start_time bigint unsigned NOT NULL,
+  end_time bigint unsigned NULL DEFAULT NULL,
+  duration bigint unsigned NULL DEFAULT NULL,
+  outcome enum('progressing', 'stalled', 'complete', 'superseded', 'deleted') COLLATE utf8mb4_unicode_ci NOT NULL,
+  message text NOT NULL,
+  PRIMARY KEY (uuid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+CREATE TABLE node_metrics (
+  cluster_uuid binary(16) NOT NULL,
+  node_name varchar(253) NOT NULL,
+  timestamp bigint unsigned NOT NULL,
+  duration bigint unsigned NOT NULL,
+  cpu_usage float NOT NULL,
+  memory_usage float NOT NULL,
+  PRIMARY KEY (cluster_uuid, node_name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+DROP TABLE pod_metrics;
+CREATE TABLE pod_metrics (
+  cluster_uuid binary(16) NOT NULL,
+  namespace varchar(255) NOT NULL,
+  pod_name varchar(253) NOT NULL,
+  container_name varchar(255) NOT NULL,
+  timestamp bigint unsigned NOT NULL,
+  duration bigint unsigned NOT NULL,
+  cpu_usage float NOT NULL,
+  memory_usage float NOT NULL,
+  storage_usage float NOT NULL,
+  ephemeral_storage_usage float NOT NULL,
+  PRIMARY KEY (cluster_uuid, namespace, pod_name, container_name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
@@ -0,0 +1,19 @@
+package pgsql
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// Schema is a copy of schema.sql. It resides here
+// and not in ../../cmd/icinga-kubernetes/main.go due to go:embed restrictions.
+//
+//go:embed schema.sql
+var Schema string
+
+//go:embed all:upgrades
+var upgrades embed.FS
+
+// Upgrades contains the versioned upgrade scripts from the upgrades directory,
+// each named after the schema version it upgrades to, e.g. 0.3.0.sql.
+var Upgrades, _ = fs.Sub(upgrades, "upgrades")
@@ -0,0 +1,218 @@
+CREATE TABLE hpa (
+  uuid bytea NOT NULL,
+  cluster_uuid bytea NOT NULL,
+  namespace varchar(255) NOT NULL,
+  name varchar(253) NOT NULL,
+  uid varchar(255) NOT NULL,
+  resource_version varchar(255) NOT NULL,
+  scale_target_kind varchar(255) NOT NULL,
+  scale_target_name varchar(253) NOT NULL,
+  min_replicas bigint NOT NULL,
+  max_replicas bigint NOT NULL,
+  current_replicas bigint NOT NULL,
+  desired_replicas bigint NOT NULL,
+  last_scale bigint DEFAULT NULL,
+  yaml text DEFAULT NULL,
+  icinga_state varchar(8) NOT NULL CHECK (lower(icinga_state) IN ('unknown', 'pending', 'ok', 'warning', 'critical')),
+  icinga_state_reason text NOT NULL,
+  created bigint NOT NULL,
+  CONSTRAINT pk_hpa PRIMARY KEY (uuid)
+);
+
+CREATE TABLE hpa_annotation (
+  hpa_uuid bytea NOT NULL,
+  annotation_uuid bytea NOT NULL,
+  CONSTRAINT pk_hpa_annotation PRIMARY KEY (hpa_uuid, annotation_uuid)
+);
+
+CREATE TABLE hpa_condition (
+  hpa_uuid bytea NOT NULL,
+  type varchar(255) NOT NULL,
+  status varchar(7) NOT NULL CHECK (lower(status) IN ('true', 'false', 'unknown')),
+  last_transition bigint NOT NULL,
+  reason varchar(255) NOT NULL,
+  message text,
+  CONSTRAINT pk_hpa_condition PRIMARY KEY (hpa_uuid, type)
+);
+
+CREATE TABLE hpa_label (
+  hpa_uuid bytea NOT NULL,
+  label_uuid bytea NOT NULL,
+  CONSTRAINT pk_hpa_label PRIMARY KEY (hpa_uuid, label_uuid)
+);
+
+CREATE TABLE hpa_metric (
+  uuid bytea NOT NULL,
+  hpa_uuid bytea NOT NULL,
+  type varchar(17) NOT NULL CHECK (lower(type) IN ('containerresource', 'external', 'object', 'pods', 'resource')),
+  name varchar(255) NOT NULL,
+  container varchar(63) DEFAULT NULL,
+  target_type varchar(12) NOT NULL CHECK (lower(target_type) IN ('averagevalue', 'utilization', 'value')),
+  target_utilization bigint DEFAULT NULL,
+  target_value varchar(255) DEFAULT NULL,
+  current_utilization bigint DEFAULT NULL,
+  current_value varchar(255) DEFAULT NULL,
+  CONSTRAINT pk_hpa_metric PRIMARY KEY (uuid)
+);
+
+CREATE TABLE pdb (
+  uuid bytea NOT NULL,
+  cluster_uuid bytea NOT NULL,
+  namespace varchar(255) NOT NULL,
+  name varchar(253) NOT NULL,
+  uid varchar(255) NOT NULL,
+  resource_version varchar(255) NOT NULL,
+  min_available varchar(255) DEFAULT NULL,
+  max_unavailable varchar(255) DEFAULT NULL,
+  unhealthy_pod_eviction_policy varchar(15) DEFAULT NULL CHECK (lower(unhealthy_pod_eviction_policy) IN ('alwaysallow', 'ifhealthybudget')),
+  current_healthy bigint NOT NULL,
+  desired_healthy bigint NOT NULL,
+  disruptions_allowed bigint NOT NULL,
+  expected_pods bigint NOT NULL,
+  yaml text DEFAULT NULL,
+  icinga_state varchar(8) NOT NULL CHECK (lower(icinga_state) IN ('unknown', 'pending', 'ok', 'warning', 'critical')),
+  icinga_state_reason text NOT NULL,
+  created bigint NOT NULL,
+  CONSTRAINT pk_pdb PRIMARY KEY (uuid)
+);
+
+CREATE TABLE pdb_annotation (
+  pdb_uuid bytea NOT NULL,
+  annotation_uuid bytea NOT NULL,
+  CONSTRAINT pk_pdb_annotation PRIMARY KEY (pdb_uuid, annotation_uuid)
+);
+
+CREATE TABLE pdb_condition (
+  pdb_uuid bytea NOT NULL,
+  type varchar(255) NOT NULL,
+  status varchar(7) NOT NULL CHECK (lower(status) IN ('true', 'false', 'unknown')),
+  last_transition bigint NOT NULL,
+  reason varchar(255) NOT NULL,
+  message text,
+  CONSTRAINT pk_pdb_condition PRIMARY KEY (pdb_uuid, type)
+);
+
+CREATE TABLE pdb_label (
+  pdb_uuid bytea NOT NULL,
+  label_uuid bytea NOT NULL,
+  CONSTRAINT pk_pdb_label PRIMARY KEY (pdb_uuid, label_uuid)
+);
+
+CREATE TABLE pdb_pod (
+  pdb_uuid bytea NOT NULL,
+  pod_uuid bytea NOT NULL,
+  CONSTRAINT pk_pdb_pod PRIMARY KEY (pdb_uuid, pod_uuid)
+);
+
+ALTER TABLE pvc
+  ADD COLUMN icinga_state varchar(8) NOT NULL DEFAULT 'unknown' CHECK (lower(icinga_state) IN ('unknown', 'pending', 'ok', 'warning', 'critical')),
+  ADD COLUMN icinga_state_reason text NOT NULL DEFAULT '';
+ALTER TABLE pvc ALTER COLUMN icinga_state DROP DEFAULT, ALTER COLUMN icinga_state_reason DROP DEFAULT;
+
+ALTER TABLE persistent_volume
+  ADD COLUMN icinga_state varchar(8) NOT NULL DEFAULT 'unknown' CHECK (lower(icinga_state) IN ('unknown', 'pending', 'ok', 'warning', 'critical')),
+  ADD COLUMN icinga_state_reason text NOT NULL DEFAULT '';
+ALTER TABLE persistent_volume ALTER COLUMN icinga_state DROP DEFAULT, ALTER COLUMN icinga_state_reason DROP DEFAULT;
+
+ALTER TABLE cron_job
+  ADD COLUMN icinga_state varchar(8) NOT NULL DEFAULT 'unknown' CHECK (lower(icinga_state) IN ('unknown', 'pending', 'ok', 'warning', 'critical')),
+  ADD COLUMN icinga_state_reason text NOT NULL DEFAULT '';
+ALTER TABLE cron_job ALTER COLUMN icinga_state DROP DEFAULT, ALTER COLUMN icinga_state_reason DROP DEFAULT;
+
+ALTER TABLE service
+  ADD COLUMN icinga_state varchar(8) NOT NULL DEFAULT 'unknown' CHECK (lower(icinga_state) IN ('unknown', 'pending', 'ok', 'warning', 'critical')),
+  ADD COLUMN icinga_state_reason text NOT NULL DEFAULT '';
+ALTER TABLE service ALTER COLUMN icinga_state DROP DEFAULT, ALTER COLUMN icinga_state_reason DROP DEFAULT;
+
+ALTER TABLE ingress
+  ADD COLUMN icinga_state varchar(8) NOT NULL DEFAULT 'unknown' CHECK (lower(icinga_state) IN ('unknown', 'pending', 'ok', 'warning', 'critical')),
+  ADD COLUMN icinga_state_reason text NOT NULL DEFAULT '';
+ALTER TABLE ingress ALTER COLUMN icinga_state DROP DEFAULT, ALTER COLUMN icinga_state_reason DROP DEFAULT;
+
+ALTER TABLE config DROP CONSTRAINT config_key_check, ADD CONSTRAINT config_key_check CHECK (lower("key") IN (
+    'notifications.url',
+    'notifications.username',
+    'notifications.password',
+    'notifications.kubernetes_web_url',
+    'prometheus.url',
+    'prometheus.username',
+    'prometheus.password',
+    'retention.disabled',
+    'retention.events',
+    'retention.cluster_metrics',
+    'retention.node_metrics',
+    'retention.pod_metrics',
+    'retention.container_metrics',
+    'retention.rollouts'
+    ));
+
+ALTER TABLE kubernetes_instance
+  ADD COLUMN role varchar(8) NOT NULL DEFAULT 'leader' CHECK (lower(role) IN ('leader', 'follower'));
+ALTER TABLE kubernetes_instance ALTER COLUMN role DROP DEFAULT;
+
+CREATE TABLE notification_outbox (
+  uuid bytea NOT NULL,
+  sink varchar(64) NOT NULL,
+  cluster_uuid bytea NOT NULL,
+  event text NOT NULL,
+  created bigint NOT NULL,
+  CONSTRAINT pk_notification_outbox PRIMARY KEY (uuid, sink)
+);
+
+CREATE TABLE notification_silence (
+  uuid bytea NOT NULL,
+  cluster_uuid bytea NOT NULL,
+  silenced_until bigint DEFAULT NULL,
+  maintenance boolenum NOT NULL,
+  namespace varchar(255) DEFAULT NULL,
+  since bigint NOT NULL,
+  changes bigint NOT NULL,
+  event text NOT NULL,
+  CONSTRAINT pk_notification_silence PRIMARY KEY (uuid)
+);
+
+ALTER TABLE namespace
+  ADD COLUMN icinga_state varchar(8) NOT NULL DEFAULT 'unknown' CHECK (lower(icinga_state) IN ('unknown', 'pending', 'ok', 'warning', 'critical')),
+  ADD COLUMN icinga_state_reason text NOT NULL DEFAULT '';
+ALTER TABLE namespace ALTER COLUMN icinga_state DROP DEFAULT, ALTER COLUMN icinga_state_reason DROP DEFAULT;
+
+CREATE TABLE rollout (
+  uuid bytea NOT NULL,
+  cluster_uuid bytea NOT NULL,
+  resource_uuid bytea NOT NULL,
+  kind varchar(12) NOT NULL CHECK (lower(kind) IN ('daemon_set', 'deployment', 'stateful_set')),
+  namespace varchar(255) NOT NULL,
+  name varchar(253) NOT NULL,
+  generation bigint NOT NULL,
+  start_time bigint NOT NULL,
+  end_time bigint DEFAULT NULL,
+  duration bigint DEFAULT NULL,
+  outcome varchar(11) NOT NULL CHECK (lower(outcome) IN ('progressing', 'stalled', 'complete', 'superseded', 'deleted')),
+  message text NOT NULL,
+  CONSTRAINT pk_rollout PRIMARY KEY (uuid)
+);
+
+CREATE TABLE node_metrics (
+  cluster_uuid bytea NOT NULL,
+  node_name varchar(253) NOT NULL,
+  timestamp bigint NOT NULL,
+  duration bigint NOT NULL,
+  cpu_usage real NOT NULL,
+  memory_usage real NOT NULL,
+  CONSTRAINT pk_node_metrics PRIMARY KEY (cluster_uuid, node_name)
+);
+
+DROP TABLE pod_metrics;
+CREATE TABLE pod_metrics (
+  cluster_uuid bytea NOT NULL,
+  namespace varchar(255) NOT NULL,
+  pod_name varchar(253) NOT NULL,
+  container_name varchar(255) NOT NULL,
+  timestamp bigint NOT NULL,
+  duration bigint NOT NULL,
+  cpu_usage real NOT NULL,
+  memory_usage real NOT NULL,
+  storage_usage real NOT NULL,
+  ephemeral_storage_usage real NOT NULL,
+  CONSTRAINT pk_pod_metrics PRIMARY KEY (cluster_uuid, namespace, pod_name, container_name)
+);